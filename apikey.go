@@ -0,0 +1,67 @@
+package reqx
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+type ApiKeyLocation string
+
+const (
+	ApiKeyInHeader ApiKeyLocation = "header"
+	ApiKeyInQuery  ApiKeyLocation = "query"
+)
+
+const redacted = "REDACTED"
+
+func (h *ClientBuilder) ApiKey(name string, value string, in ApiKeyLocation) *ClientBuilder {
+	if in == ApiKeyInQuery {
+		h.redactedParams = append(h.redactedParams, name)
+	} else {
+		h.redactedHeaders = append(h.redactedHeaders, name)
+	}
+
+	return h.Auth(ApiKeyProvider{Name: name, Value: value, In: in})
+}
+
+func (c *Client) redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	changed := u.User != nil
+	u.User = nil
+
+	query := u.Query()
+	for _, name := range c.redactedParams {
+		if query.Has(name) {
+			query.Set(name, redacted)
+			u.RawQuery = query.Encode()
+			changed = true
+		}
+	}
+	if !changed {
+		return raw
+	}
+
+	return u.String()
+}
+
+func (c *Client) isRedactedHeader(name string) bool {
+	return slices.ContainsFunc(c.redactedHeaders, func(header string) bool {
+		return strings.EqualFold(header, name)
+	})
+}
+
+func (c *Client) transportError(ctx context.Context, method string, rawURL string, err error) *TransportError {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = c.redactURL(urlErr.URL)
+	}
+
+	return &TransportError{Method: method, URL: c.redactURL(rawURL), Err: canceledCause(ctx, err)}
+}
@@ -0,0 +1,179 @@
+package reqx
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+type UnsafeArchivePathError struct {
+	Name string
+}
+
+func (e *UnsafeArchivePathError) Error() string {
+	return fmt.Sprintf("reqx.unsafe_archive_path: %q", e.Name)
+}
+
+func (e *UnsafeArchivePathError) Is(target error) bool {
+	return target == ErrUnsafeArchivePath
+}
+
+// Tar archives are extracted while they stream; zip archives need random
+// access and are spooled to a temporary file first.
+func (c *RequestBuilder) ExtractTo(dir string) ([]string, error) {
+	resp, err := c.DoStream()
+	if err != nil {
+		return nil, err
+	}
+	defer resp.BodyReader.Close()
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	reader := bufio.NewReader(resp.BodyReader)
+	magic, _ := reader.Peek(4)
+
+	switch {
+	case bytes.HasPrefix(magic, []byte("PK\x03\x04")):
+		return extractZip(root, reader)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		return extractTar(root, gz)
+	default:
+		return extractTar(root, reader)
+	}
+}
+
+func extractTar(root *os.Root, reader io.Reader) ([]string, error) {
+	var extracted []string
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return extracted, nil
+		}
+		if err != nil {
+			return extracted, err
+		}
+
+		name, err := archivePath(header.Name)
+		if err != nil {
+			return extracted, err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = root.MkdirAll(name, 0o755)
+		case tar.TypeReg:
+			err = writeArchiveFile(root, name, fs.FileMode(header.Mode), archive)
+			extracted = append(extracted, name)
+		default:
+			// Links and special files are skipped so the archive cannot
+			// point outside the target directory.
+			continue
+		}
+		if err != nil {
+			return extracted, err
+		}
+	}
+}
+
+func extractZip(root *os.Root, reader io.Reader) ([]string, error) {
+	spool, err := os.CreateTemp("", "reqx-*.zip")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	size, err := io.Copy(spool, reader)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := zip.NewReader(spool, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var extracted []string
+	for _, file := range archive.File {
+		name, err := archivePath(file.Name)
+		if err != nil {
+			return extracted, err
+		}
+
+		mode := file.Mode()
+		switch {
+		case mode.IsDir():
+			err = root.MkdirAll(name, 0o755)
+		case mode.IsRegular():
+			err = extractZipFile(root, name, file)
+			extracted = append(extracted, name)
+		default:
+			continue
+		}
+		if err != nil {
+			return extracted, err
+		}
+	}
+
+	return extracted, nil
+}
+
+func extractZipFile(root *os.Root, name string, file *zip.File) error {
+	content, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	return writeArchiveFile(root, name, file.Mode(), content)
+}
+
+func writeArchiveFile(root *os.Root, name string, mode fs.FileMode, content io.Reader) error {
+	if dir := path.Dir(name); dir != "." {
+		if err := root.MkdirAll(filepath.FromSlash(dir), 0o755); err != nil {
+			return err
+		}
+	}
+
+	out, err := root.OpenFile(filepath.FromSlash(name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(out, content)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func archivePath(name string) (string, error) {
+	cleaned := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") || filepath.VolumeName(cleaned) != "" {
+		return "", &UnsafeArchivePathError{Name: name}
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,168 @@
+package reqx
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+type archiveEntry struct {
+	name     string
+	body     string
+	linkname string
+}
+
+func tarArchive(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: 0o644, Size: int64(len(entry.body)), Typeflag: tar.TypeReg}
+		if entry.linkname != "" {
+			header = &tar.Header{Name: entry.name, Linkname: entry.linkname, Typeflag: tar.TypeSymlink}
+		}
+		if err := archive.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := archive.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func zipArchive(t *testing.T, entries ...archiveEntry) []byte {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		w, err := archive.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry.body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func extract(t *testing.T, payload []byte, dir string) ([]string, error) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	defer client.Close()
+
+	return client.Get("/export").ExtractTo(dir)
+}
+
+func TestExtractArchives(t *testing.T) {
+	entries := []archiveEntry{{name: "report.csv", body: "a,b"}, {name: "data/items.json", body: "[]"}}
+	payloads := map[string][]byte{
+		"tar":    tarArchive(t, entries...),
+		"tar.gz": gzipped(t, tarArchive(t, entries...)),
+		"zip":    zipArchive(t, entries...),
+	}
+
+	for format, payload := range payloads {
+		t.Run(format, func(t *testing.T) {
+			dir := t.TempDir()
+			files, err := extract(t, payload, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(files, []string{"report.csv", "data/items.json"}) {
+				t.Errorf("files = %v", files)
+			}
+			for _, entry := range entries {
+				data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(entry.name)))
+				if err != nil || string(data) != entry.body {
+					t.Errorf("%s = %q, %v; want %q", entry.name, data, err, entry.body)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractRejectsUnsafePaths(t *testing.T) {
+	for _, name := range []string{"../escaped.txt", "nested/../../escaped.txt", "/escaped.txt", `..\escaped.txt`} {
+		payloads := map[string][]byte{
+			"tar": tarArchive(t, archiveEntry{name: name, body: "x"}),
+			"zip": zipArchive(t, archiveEntry{name: name, body: "x"}),
+		}
+		for format, payload := range payloads {
+			t.Run(format+" "+name, func(t *testing.T) {
+				parent := t.TempDir()
+				dir := filepath.Join(parent, "out")
+
+				_, err := extract(t, payload, dir)
+				if !errors.Is(err, ErrUnsafeArchivePath) {
+					t.Fatalf("err = %v, want ErrUnsafeArchivePath", err)
+				}
+				if _, err := os.Stat(filepath.Join(parent, "escaped.txt")); !os.IsNotExist(err) {
+					t.Error("an entry was written outside the target directory")
+				}
+			})
+		}
+	}
+}
+
+func TestExtractDoesNotFollowSymlinks(t *testing.T) {
+	outside := t.TempDir()
+	dir := t.TempDir()
+
+	payload := tarArchive(t,
+		archiveEntry{name: "link", linkname: outside},
+		archiveEntry{name: "link/escaped.txt", body: "x"},
+	)
+	if _, err := extract(t, payload, dir); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(filepath.Join(dir, "link")); err != nil || info.Mode()&os.ModeSymlink != 0 {
+		t.Errorf("symlink entry was extracted: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escaped.txt")); !os.IsNotExist(err) {
+		t.Error("an entry was written through a symlink")
+	}
+
+	// A symlink already present in the target directory is not followed
+	// either.
+	dir = t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(dir, "existing")); err != nil {
+		t.Skip(err)
+	}
+	payload = tarArchive(t, archiveEntry{name: "existing/escaped.txt", body: "x"})
+	if _, err := extract(t, payload, dir); err == nil {
+		t.Error("extraction through an existing symlink succeeded")
+	}
+	if _, err := os.Stat(filepath.Join(outside, "escaped.txt")); !os.IsNotExist(err) {
+		t.Error("an entry was written through an existing symlink")
+	}
+}
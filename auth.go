@@ -0,0 +1,109 @@
+package reqx
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+type AuthProvider interface {
+	Apply(req *http.Request) error
+}
+
+type AuthFunc func(req *http.Request) error
+
+func (f AuthFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+type BasicAuthProvider struct {
+	Username string
+	Password string
+}
+
+func (p BasicAuthProvider) Apply(req *http.Request) error {
+	credentials := base64.StdEncoding.EncodeToString([]byte(p.Username + ":" + p.Password))
+	req.Header.Set("Authorization", "Basic "+credentials)
+	return nil
+}
+
+type BearerAuthProvider struct {
+	Token string
+}
+
+func (p BearerAuthProvider) Apply(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+p.Token)
+	return nil
+}
+
+type ApiKeyProvider struct {
+	Name  string
+	Value string
+	In    ApiKeyLocation
+}
+
+func (p ApiKeyProvider) Apply(req *http.Request) error {
+	if p.In == ApiKeyInQuery {
+		query := req.URL.Query()
+		query.Set(p.Name, p.Value)
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+
+	req.Header.Set(p.Name, p.Value)
+	return nil
+}
+
+type TokenSourceProvider struct {
+	Source TokenSource
+}
+
+func (p TokenSourceProvider) Apply(req *http.Request) error {
+	token, err := p.Source.Token(req.Context())
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+func (h *ClientBuilder) Auth(provider AuthProvider) *ClientBuilder {
+	h.auth = provider
+	return h
+}
+
+func (c *RequestBuilder) Auth(provider AuthProvider) *RequestBuilder {
+	c.auth = provider
+	c.noAuth = false
+	return c
+}
+
+func (c *RequestBuilder) BearerAuth(token string) *RequestBuilder {
+	return c.Auth(BearerAuthProvider{Token: token})
+}
+
+func (c *RequestBuilder) BasicAuth(username string, password string) *RequestBuilder {
+	return c.Auth(BasicAuthProvider{Username: username, Password: password})
+}
+
+func (c *RequestBuilder) NoAuth() *RequestBuilder {
+	c.auth = nil
+	c.noAuth = true
+	return c
+}
+
+func (c *RequestBuilder) authProvider() AuthProvider {
+	if c.noAuth {
+		return nil
+	}
+	if c.auth != nil {
+		return c.auth
+	}
+
+	return c.client.auth
+}
+
+func (c *RequestBuilder) isCredentialHeader(name string) bool {
+	return strings.EqualFold(name, "Authorization") || c.client.isRedactedHeader(name)
+}
@@ -0,0 +1,120 @@
+package reqx
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type BackoffStrategy int
+
+const (
+	BackoffLinear BackoffStrategy = iota
+	BackoffExponential
+)
+
+type JitterMode int
+
+const (
+	JitterNone JitterMode = iota
+	JitterFull
+	JitterEqual
+	JitterDecorrelated
+)
+
+func (h *ClientBuilder) RetryBackoff(strategy BackoffStrategy, maxBackoffMs int, jitter JitterMode) *ClientBuilder {
+	h.retryConfig.Strategy = strategy
+	h.retryConfig.MaxBackoffMs = maxBackoffMs
+	h.retryConfig.Jitter = jitter
+	return h
+}
+
+func (h *ClientBuilder) RetryAfterCap(maxWait time.Duration) *ClientBuilder {
+	h.retryConfig.MaxRetryAfter = maxWait
+	return h
+}
+
+func (h *ClientBuilder) IgnoreRetryAfter() *ClientBuilder {
+	h.retryConfig.IgnoreRetryAfter = true
+	return h
+}
+
+func (c *RetryConfig) retryAfter(resp *Response) (time.Duration, bool) {
+	if c.IgnoreRetryAfter || resp == nil ||
+		resp.Status != http.StatusTooManyRequests && resp.Status != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	wait, ok := parseRetryAfter(resp.Headers.Get("Retry-After"), time.Now())
+	if !ok {
+		return 0, false
+	}
+	if c.MaxRetryAfter > 0 {
+		wait = min(wait, c.MaxRetryAfter)
+	}
+
+	return wait, true
+}
+
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+func (c *RetryConfig) backoff(attempt int, previous time.Duration) time.Duration {
+	base := time.Duration(c.BackoffMs) * time.Millisecond
+
+	limit := time.Duration(math.MaxInt64)
+	if c.MaxBackoffMs > 0 {
+		limit = time.Duration(c.MaxBackoffMs) * time.Millisecond
+	}
+
+	if c.Jitter == JitterDecorrelated {
+		upper := max(base, previous*3)
+		return min(limit, base+randDuration(upper-base))
+	}
+
+	var delay time.Duration
+	switch c.Strategy {
+	case BackoffExponential:
+		growth := math.Pow(2, float64(attempt))
+		if float64(base)*growth >= float64(limit) {
+			delay = limit
+		} else {
+			delay = time.Duration(float64(base) * growth)
+		}
+	default:
+		delay = base * time.Duration(attempt+1)
+	}
+	delay = min(limit, delay)
+
+	switch c.Jitter {
+	case JitterFull:
+		return randDuration(delay)
+	case JitterEqual:
+		return delay/2 + randDuration(delay/2)
+	default:
+		return delay
+	}
+}
+
+func randDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+
+	return rand.N(d + 1)
+}
@@ -0,0 +1,157 @@
+package reqx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type BatchConfig struct {
+	MaxItems   int
+	MaxLatency time.Duration
+}
+
+type batchResult[Out any] struct {
+	value Out
+	err   error
+}
+
+type batchItem[In, Out any] struct {
+	value  In
+	result chan batchResult[Out]
+}
+
+type Batcher[In, Out any] struct {
+	client *Client
+	path   string
+	config BatchConfig
+
+	mu      sync.Mutex
+	pending []batchItem[In, Out]
+	timer   *time.Timer
+	closed  bool
+	flushes sync.WaitGroup
+}
+
+func NewBatcher[In, Out any](client *Client, path string, config BatchConfig) *Batcher[In, Out] {
+	if config.MaxItems < 1 {
+		config.MaxItems = 100
+	}
+	if config.MaxLatency <= 0 {
+		config.MaxLatency = 50 * time.Millisecond
+	}
+
+	b := &Batcher[In, Out]{
+		client: client,
+		path:   path,
+		config: config,
+	}
+	client.onClose(b.Close)
+
+	return b
+}
+
+func (b *Batcher[In, Out]) Submit(ctx context.Context, value In) (Out, error) {
+	item := batchItem[In, Out]{
+		value:  value,
+		result: make(chan batchResult[Out], 1),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		var zero Out
+		return zero, ErrBatcherClosed
+	}
+
+	b.pending = append(b.pending, item)
+	if len(b.pending) >= b.config.MaxItems {
+		b.flushLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.config.MaxLatency, b.Flush)
+	}
+	b.mu.Unlock()
+
+	select {
+	case result := <-item.result:
+		return result.value, result.err
+	case <-ctx.Done():
+		var zero Out
+		return zero, ctx.Err()
+	}
+}
+
+func (b *Batcher[In, Out]) Flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flushLocked()
+}
+
+func (b *Batcher[In, Out]) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	b.flushLocked()
+	b.mu.Unlock()
+
+	b.flushes.Wait()
+}
+
+func (b *Batcher[In, Out]) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if len(b.pending) == 0 {
+		return
+	}
+
+	batch := b.pending
+	b.pending = nil
+
+	b.flushes.Add(1)
+	go func() {
+		defer b.flushes.Done()
+		b.send(batch)
+	}()
+}
+
+func (b *Batcher[In, Out]) send(batch []batchItem[In, Out]) {
+	values := make([]In, len(batch))
+	for i, item := range batch {
+		values[i] = item.value
+	}
+
+	results, err := b.post(values)
+	if err == nil && len(results) != len(batch) {
+		err = fmt.Errorf("%w: sent %d items, received %d results", ErrBatchMismatch, len(batch), len(results))
+	}
+
+	for i, item := range batch {
+		if err != nil {
+			item.result <- batchResult[Out]{err: err}
+			continue
+		}
+		item.result <- batchResult[Out]{value: results[i]}
+	}
+}
+
+func (b *Batcher[In, Out]) post(values []In) ([]Out, error) {
+	resp, err := b.client.Post(b.path).JsonContentType().Body(values).DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Out
+	if err := json.Unmarshal(resp.Body, &results); err != nil {
+		return nil, &DecodeError{Status: resp.Status, Body: resp.Body, Err: err}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,77 @@
+package reqx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatcherFlushesPendingItemsOnClientClose(t *testing.T) {
+	var mu sync.Mutex
+	var received []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var values []int
+		if err := json.NewDecoder(r.Body).Decode(&values); err != nil {
+			t.Errorf("decode batch: %v", err)
+		}
+		mu.Lock()
+		received = append(received, values...)
+		mu.Unlock()
+
+		results := make([]int, len(values))
+		for i, v := range values {
+			results[i] = v * 2
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	batcher := NewBatcher[int, int](client, "/batch", BatchConfig{MaxItems: 100, MaxLatency: time.Hour})
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	results := make([]int, 3)
+	for i := range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = batcher.Submit(context.Background(), i+1)
+		}()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		batcher.mu.Lock()
+		pending := len(batcher.pending)
+		batcher.mu.Unlock()
+		if pending == 3 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("pending = %d, want 3", pending)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("item %d: %v", i, err)
+		}
+		if results[i] != (i+1)*2 {
+			t.Errorf("item %d = %d, want %d", i, results[i], (i+1)*2)
+		}
+	}
+	if len(received) != 3 {
+		t.Errorf("server received %v, want 3 items", received)
+	}
+}
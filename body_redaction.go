@@ -0,0 +1,64 @@
+package reqx
+
+import (
+	"mime"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type bodyRedactor struct {
+	fields []string
+	json   *regexp.Regexp
+}
+
+func newBodyRedactor(fields []string) *bodyRedactor {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = regexp.QuoteMeta(field)
+	}
+	return &bodyRedactor{
+		fields: fields,
+		json:   regexp.MustCompile(`("(?i:` + strings.Join(quoted, "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"?`),
+	}
+}
+
+// JSON values are replaced in place rather than by re-encoding, so bodies
+// cut at the size limit are redacted as well.
+func (r *bodyRedactor) redact(body []byte, contentType string) []byte {
+	if r == nil || len(body) == 0 {
+		return body
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == string(ContentTypeFormUrlencoded) {
+		return r.redactForm(body)
+	}
+	return r.json.ReplaceAll(body, []byte(`${1}"`+redacted+`"`))
+}
+
+func (r *bodyRedactor) redactForm(body []byte) []byte {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+
+	changed := false
+	for name, values := range form {
+		if slices.ContainsFunc(r.fields, func(field string) bool { return strings.EqualFold(field, name) }) {
+			for i := range values {
+				values[i] = redacted
+			}
+			changed = true
+		}
+	}
+	if !changed {
+		return body
+	}
+	return []byte(form.Encode())
+}
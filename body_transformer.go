@@ -0,0 +1,81 @@
+package reqx
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+)
+
+type BodyTransformer interface {
+	TransformRequest(body []byte, headers http.Header) ([]byte, error)
+	TransformResponse(body []byte, headers http.Header) ([]byte, error)
+}
+
+func (h *ClientBuilder) BodyTransformer(transformers ...BodyTransformer) *ClientBuilder {
+	h.bodyTransformers = append(h.bodyTransformers, transformers...)
+	return h
+}
+
+func (c *RequestBuilder) BodyTransformer(transformers ...BodyTransformer) *RequestBuilder {
+	c.bodyTransformers = append(c.bodyTransformers, transformers...)
+	return c
+}
+
+func (c *RequestBuilder) transformers() []BodyTransformer {
+	if len(c.bodyTransformers) == 0 {
+		return c.client.bodyTransformers
+	}
+
+	transformers := make([]BodyTransformer, 0, len(c.client.bodyTransformers)+len(c.bodyTransformers))
+	transformers = append(transformers, c.client.bodyTransformers...)
+	return append(transformers, c.bodyTransformers...)
+}
+
+func (c *RequestBuilder) transformRequestBody(req *http.Request) error {
+	transformers := c.transformers()
+	if len(transformers) == 0 || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	if err := req.Body.Close(); err != nil {
+		return err
+	}
+
+	for _, transformer := range transformers {
+		body, err = transformer.TransformRequest(body, req.Header)
+		if err != nil {
+			return err
+		}
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return nil
+}
+
+func (c *RequestBuilder) transformResponseBody(response *Response) error {
+	transformers := c.transformers()
+	if len(transformers) == 0 || len(response.Body) == 0 {
+		return nil
+	}
+
+	body := response.Body
+	for i := len(transformers) - 1; i >= 0; i-- {
+		var err error
+		body, err = transformers[i].TransformResponse(body, response.Headers)
+		if err != nil {
+			return err
+		}
+	}
+
+	response.Body = body
+	return nil
+}
@@ -0,0 +1,131 @@
+package reqx
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// bulkhead admits queued requests by urgency, then in arrival order. A
+// released slot is handed straight to the next waiter.
+type bulkhead struct {
+	mu       sync.Mutex
+	capacity int
+	inUse    int
+	waiters  waiterQueue
+	sequence uint64
+	timeout  time.Duration
+}
+
+type waiter struct {
+	urgency  int
+	sequence uint64
+	index    int
+	granted  bool
+	ready    chan struct{}
+}
+
+type waiterQueue []*waiter
+
+func (q waiterQueue) Len() int { return len(q) }
+
+func (q waiterQueue) Less(i, j int) bool {
+	if q[i].urgency != q[j].urgency {
+		return q[i].urgency < q[j].urgency
+	}
+	return q[i].sequence < q[j].sequence
+}
+
+func (q waiterQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *waiterQueue) Push(x any) {
+	w := x.(*waiter)
+	w.index = len(*q)
+	*q = append(*q, w)
+}
+
+func (q *waiterQueue) Pop() any {
+	old := *q
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return w
+}
+
+func (h *ClientBuilder) MaxConcurrent(n int) *ClientBuilder {
+	h.maxConcurrent = n
+	return h
+}
+
+func (h *ClientBuilder) QueueTimeout(timeout time.Duration) *ClientBuilder {
+	h.queueTimeout = timeout
+	return h
+}
+
+func newBulkhead(n int, timeout time.Duration) *bulkhead {
+	if n <= 0 {
+		return nil
+	}
+
+	return &bulkhead{capacity: n, timeout: timeout}
+}
+
+func (b *bulkhead) acquire(ctx context.Context, urgency int) (func(), error) {
+	b.mu.Lock()
+	if b.inUse < b.capacity && b.waiters.Len() == 0 {
+		b.inUse++
+		b.mu.Unlock()
+		return b.release, nil
+	}
+
+	b.sequence++
+	w := &waiter{urgency: urgency, sequence: b.sequence, ready: make(chan struct{})}
+	heap.Push(&b.waiters, w)
+	b.mu.Unlock()
+
+	var expired <-chan time.Time
+	if b.timeout > 0 {
+		timer := time.NewTimer(b.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+		return b.release, nil
+	case <-expired:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = canceledCause(ctx, ctx.Err())
+	}
+
+	b.mu.Lock()
+	if w.granted {
+		b.mu.Unlock()
+		b.release()
+		return nil, err
+	}
+	heap.Remove(&b.waiters, w.index)
+	b.mu.Unlock()
+	return nil, err
+}
+
+func (b *bulkhead) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.waiters.Len() == 0 {
+		b.inUse--
+		return
+	}
+
+	w := heap.Pop(&b.waiters).(*waiter)
+	w.granted = true
+	close(w.ready)
+}
@@ -0,0 +1,110 @@
+package reqx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const cacheSnapshotVersion = 1
+
+type cacheSnapshot struct {
+	Version int                  `json:"version"`
+	Entries []cacheSnapshotEntry `json:"entries"`
+}
+
+type cacheSnapshotEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	Status       int         `json:"status"`
+	Headers      http.Header `json:"headers"`
+	Body         []byte      `json:"body,omitempty"`
+}
+
+func (c *Client) ExportCache(w io.Writer) error {
+	if c.conditional == nil {
+		return ErrCacheDisabled
+	}
+
+	snapshot := cacheSnapshot{Version: cacheSnapshotVersion}
+
+	c.conditional.mu.Lock()
+	for key, entry := range c.conditional.entries {
+		snapshot.Entries = append(snapshot.Entries, cacheSnapshotEntry{
+			URL:          key,
+			ETag:         entry.etag,
+			LastModified: entry.lastModified,
+			Status:       entry.status,
+			Headers:      entry.headers,
+			Body:         entry.body,
+		})
+	}
+	c.conditional.mu.Unlock()
+
+	return json.NewEncoder(w).Encode(snapshot)
+}
+
+// Imported entries are only used to revalidate, so a stale snapshot costs a
+// full response at worst.
+func (c *Client) ImportCache(r io.Reader) error {
+	if c.conditional == nil {
+		return ErrCacheDisabled
+	}
+
+	var snapshot cacheSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return err
+	}
+	if snapshot.Version != cacheSnapshotVersion {
+		return ErrCacheSnapshot
+	}
+
+	c.conditional.mu.Lock()
+	defer c.conditional.mu.Unlock()
+
+	for _, entry := range snapshot.Entries {
+		if _, ok := c.conditional.entries[entry.URL]; ok || len(c.conditional.entries) >= maxConditionalEntries {
+			continue
+		}
+		c.conditional.entries[entry.URL] = &conditionalEntry{
+			etag:         entry.ETag,
+			lastModified: entry.LastModified,
+			status:       entry.Status,
+			headers:      entry.Headers,
+			body:         entry.Body,
+		}
+	}
+
+	return nil
+}
+
+func (c *Client) SaveCache(path string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := c.ExportCache(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (c *Client) LoadCache(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return c.ImportCache(file)
+}
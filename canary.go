@@ -0,0 +1,119 @@
+package reqx
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+type CanaryConfig struct {
+	BaseUrl string
+	Percent float64
+}
+
+type CanaryTarget string
+
+const (
+	CanaryTargetPrimary CanaryTarget = "primary"
+	CanaryTargetCanary  CanaryTarget = "canary"
+)
+
+type CanaryTargetStats struct {
+	Target       CanaryTarget
+	BaseUrl      string
+	Requests     int64
+	Failures     int64
+	ServerErrors int64
+	TotalLatency time.Duration
+}
+
+func (s CanaryTargetStats) MeanLatency() time.Duration {
+	if s.Requests == 0 {
+		return 0
+	}
+	return s.TotalLatency / time.Duration(s.Requests)
+}
+
+type canary struct {
+	baseUrl string
+
+	mu      sync.Mutex
+	percent float64
+	stats   map[CanaryTarget]*CanaryTargetStats
+}
+
+func (h *ClientBuilder) Canary(config CanaryConfig) *ClientBuilder {
+	h.canary = &canary{
+		baseUrl: config.BaseUrl,
+		percent: config.Percent,
+		stats: map[CanaryTarget]*CanaryTargetStats{
+			CanaryTargetPrimary: {Target: CanaryTargetPrimary},
+			CanaryTargetCanary:  {Target: CanaryTargetCanary, BaseUrl: config.BaseUrl},
+		},
+	}
+	return h
+}
+
+func (c *Client) SetCanaryPercent(percent float64) {
+	if c.canary == nil {
+		return
+	}
+
+	c.canary.mu.Lock()
+	defer c.canary.mu.Unlock()
+
+	c.canary.percent = percent
+}
+
+func (c *Client) CanaryStats() []CanaryTargetStats {
+	if c.canary == nil {
+		return nil
+	}
+
+	c.canary.mu.Lock()
+	defer c.canary.mu.Unlock()
+
+	primary := *c.canary.stats[CanaryTargetPrimary]
+	primary.BaseUrl = c.baseUrl
+	return []CanaryTargetStats{primary, *c.canary.stats[CanaryTargetCanary]}
+}
+
+// Only requests that would go to the client's base URL are routed; explicit
+// per-request base URLs and absolute paths are left alone.
+func (c *RequestBuilder) routeCanary() (*RequestBuilder, CanaryTarget) {
+	routing := c.client.canary
+	if routing == nil || c.baseUrl != "" || isAbsoluteUrl(c.client.expandVariables(c.path)) {
+		return c, ""
+	}
+
+	routing.mu.Lock()
+	percent := routing.percent
+	routing.mu.Unlock()
+
+	if rand.Float64()*100 >= percent {
+		return c, CanaryTargetPrimary
+	}
+
+	routed := c.clone()
+	routed.baseUrl = routing.baseUrl
+	return routed, CanaryTargetCanary
+}
+
+func (c *canary) observe(target CanaryTarget, response *Response, err error, latency time.Duration) {
+	if c == nil || target == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats[target]
+	stats.Requests++
+	stats.TotalLatency += latency
+	if err != nil && response == nil {
+		stats.Failures++
+	}
+	if response != nil && response.IsServerError() {
+		stats.ServerErrors++
+	}
+}
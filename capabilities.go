@@ -0,0 +1,80 @@
+package reqx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type Capability uint
+
+const (
+	CapFollowRedirects Capability = 1 << iota
+	CapDecompression
+	CapRetries
+	CapCookies
+	CapAutoDecode
+)
+
+const (
+	NoCapabilities      Capability = 0
+	DefaultCapabilities            = CapFollowRedirects | CapDecompression | CapRetries | CapAutoDecode
+)
+
+var capabilityNames = []struct {
+	capability Capability
+	name       string
+}{
+	{CapFollowRedirects, "follow_redirects"},
+	{CapDecompression, "decompression"},
+	{CapRetries, "retries"},
+	{CapCookies, "cookies"},
+	{CapAutoDecode, "auto_decode"},
+}
+
+func (c Capability) Has(capability Capability) bool {
+	return c&capability == capability
+}
+
+func (c Capability) String() string {
+	var names []string
+	for _, entry := range capabilityNames {
+		if c.Has(entry.capability) {
+			names = append(names, entry.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+func (h *ClientBuilder) Capabilities(capabilities Capability) *ClientBuilder {
+	h.capabilities = capabilities
+	return h
+}
+
+func (c *Client) Capabilities() Capability {
+	return c.capabilities
+}
+
+func (c *Client) require(capability Capability) error {
+	if !c.capabilities.Has(capability) {
+		return fmt.Errorf("%w: %s", ErrCapabilityDisabled, capability)
+	}
+	return nil
+}
+
+func (h *ClientBuilder) checkRedirect() func(req *http.Request, via []*http.Request) error {
+	if !h.capabilities.Has(CapFollowRedirects) {
+		return func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+	if h.firewall != nil {
+		return h.firewall.checkRedirect
+	}
+
+	return nil
+}
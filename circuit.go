@@ -0,0 +1,170 @@
+package reqx
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	CoolDown         time.Duration
+	HalfOpenRequests int
+	IsFailure        func(resp *http.Response, err error) bool
+}
+
+type CircuitOpenError struct {
+	Host    string
+	RetryAt time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("reqx.circuit_open: %s until %s", e.Host, e.RetryAt.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+type circuitBreaker struct {
+	config CircuitBreakerConfig
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+func (h *ClientBuilder) CircuitBreaker(config CircuitBreakerConfig) *ClientBuilder {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode >= http.StatusInternalServerError
+		}
+	}
+
+	h.breaker = &circuitBreaker{config: config, hosts: make(map[string]*circuit)}
+	return h
+}
+
+func (c *Client) CircuitState(host string) CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+
+	if state, ok := c.breaker.hosts[host]; ok {
+		return state.state
+	}
+	return CircuitClosed
+}
+
+// allow reports whether the request took one of the half-open trials, which
+// must end in either record or release.
+func (b *circuitBreaker) allow(host string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.hosts[host]
+	if !ok {
+		return false, nil
+	}
+
+	if state.state == CircuitOpen {
+		retryAt := state.openedAt.Add(b.config.CoolDown)
+		if time.Now().Before(retryAt) {
+			return false, &CircuitOpenError{Host: host, RetryAt: retryAt}
+		}
+		state.state = CircuitHalfOpen
+		state.trials = 0
+	}
+
+	if state.state == CircuitHalfOpen {
+		if state.trials >= b.config.HalfOpenRequests {
+			return false, &CircuitOpenError{Host: host, RetryAt: time.Now().Add(b.config.CoolDown)}
+		}
+		state.trials++
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// release hands back a trial that ended without a verdict, e.g. because the
+// request was canceled or rejected by a quota before it was sent.
+func (b *circuitBreaker) release(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if state, ok := b.hosts[host]; ok && state.state == CircuitHalfOpen && state.trials > 0 {
+		state.trials--
+	}
+}
+
+func (b *circuitBreaker) record(host string, resp *http.Response, err error) {
+	failed := b.config.IsFailure(resp, err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.hosts[host]
+	if !ok {
+		if !failed {
+			return
+		}
+		state = &circuit{}
+		b.hosts[host] = state
+	}
+
+	switch {
+	case !failed:
+		state.state = CircuitClosed
+		state.failures = 0
+	case state.state == CircuitHalfOpen:
+		state.state = CircuitOpen
+		state.openedAt = time.Now()
+	default:
+		state.failures++
+		if state.failures >= b.config.FailureThreshold {
+			state.state = CircuitOpen
+			state.openedAt = time.Now()
+		}
+	}
+}
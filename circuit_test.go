@@ -0,0 +1,91 @@
+package reqx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func openCircuitClient(t *testing.T, handler http.HandlerFunc) (*Client, *httptest.Server) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		RetryConfig(0, 0).
+		CircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, CoolDown: 20 * time.Millisecond}).
+		Build()
+	t.Cleanup(func() { client.Close() })
+	return client, server
+}
+
+func TestCircuitOpensAndRecovers(t *testing.T) {
+	var healthy atomic.Bool
+	client, server := openCircuitClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	host := server.Listener.Addr().String()
+
+	client.Get("/").DoRaw()
+	if state := client.CircuitState(host); state != CircuitOpen {
+		t.Fatalf("state = %v, want open", state)
+	}
+	if _, err := client.Get("/").DoRaw(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+
+	healthy.Store(true)
+	time.Sleep(30 * time.Millisecond)
+	if _, err := client.Get("/").DoRaw(); err != nil {
+		t.Fatalf("trial request: %v", err)
+	}
+	if state := client.CircuitState(host); state != CircuitClosed {
+		t.Fatalf("state = %v, want closed", state)
+	}
+}
+
+func TestCanceledHalfOpenTrialIsReleased(t *testing.T) {
+	var healthy atomic.Bool
+	blocked := make(chan struct{})
+	client, server := openCircuitClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(blocked)
+			<-r.Context().Done()
+			return
+		}
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	host := server.Listener.Addr().String()
+
+	client.Get("/").DoRaw()
+	time.Sleep(30 * time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-blocked
+		cancel()
+	}()
+	if _, err := client.Get("/slow").Context(ctx).DoRaw(); err == nil {
+		t.Fatal("canceled trial succeeded")
+	}
+	if state := client.CircuitState(host); state != CircuitHalfOpen {
+		t.Fatalf("state = %v, want half-open", state)
+	}
+
+	healthy.Store(true)
+	if _, err := client.Get("/").DoRaw(); err != nil {
+		t.Fatalf("request after canceled trial: %v", err)
+	}
+	if state := client.CircuitState(host); state != CircuitClosed {
+		t.Fatalf("state = %v, want closed", state)
+	}
+}
@@ -0,0 +1,187 @@
+package reqx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+type trackedBody struct {
+	io.ReadCloser
+	done func()
+	once sync.Once
+}
+
+func (t *trackedBody) Close() error {
+	err := t.ReadCloser.Close()
+	t.once.Do(t.done)
+	return err
+}
+
+func (c *Client) begin(parent context.Context) (context.Context, func(), error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, nil, ErrClientClosed
+	}
+
+	if parent == nil {
+		parent = c.context
+	}
+
+	ctx, cancel := context.WithCancelCause(parent)
+	stop := func() bool { return false }
+	if parent != c.context {
+		stop = context.AfterFunc(c.context, func() {
+			cancel(context.Cause(c.context))
+		})
+	}
+
+	c.nextRequestID++
+	id := c.nextRequestID
+	c.cancels[id] = cancel
+	c.inflight.Add(1)
+
+	done := func() {
+		c.mu.Lock()
+		delete(c.cancels, id)
+		c.mu.Unlock()
+
+		stop()
+		cancel(nil)
+		c.inflight.Done()
+	}
+
+	return ctx, done, nil
+}
+
+func (c *Client) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.cancels)
+}
+
+func (c *Client) CancelAll(reason string) int {
+	cause := fmt.Errorf("%w: %s", ErrCanceled, reason)
+
+	c.mu.Lock()
+	cancels := make([]context.CancelCauseFunc, 0, len(c.cancels))
+	for _, cancel := range c.cancels {
+		cancels = append(cancels, cancel)
+	}
+	c.mu.Unlock()
+
+	for _, cancel := range cancels {
+		cancel(cause)
+	}
+
+	return len(cancels)
+}
+
+func canceledCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil && errors.Is(cause, ErrCanceled) {
+		return cause
+	}
+
+	return err
+}
+
+// Closers run before the client shuts down, so components such as batchers
+// can still send what they hold and background work can finish.
+func (c *Client) onClose(stop func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closers = append(c.closers, stop)
+}
+
+// Releasers run once the client is closed and no request is in flight any
+// more, for resources that requests write to.
+func (c *Client) onRelease(release func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.releasers = append(c.releasers, release)
+}
+
+// bind ties a caller's context to the client's lifetime, so long-running
+// loops stop with ErrClientClosed instead of outliving the client.
+func (c *Client) bind(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+	stop := context.AfterFunc(c.context, func() {
+		cancel(ErrClientClosed)
+	})
+
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}
+}
+
+// Background work runs under the client's context, so Close cancels it and
+// waits for it before releasing shared resources.
+func (c *Client) spawn(work func()) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return false
+	}
+
+	c.background.Add(1)
+	go func() {
+		defer c.background.Done()
+		work()
+	}()
+	return true
+}
+
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed || c.closing {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closing = true
+	closers := c.closers
+	c.mu.Unlock()
+
+	for _, stop := range closers {
+		stop()
+	}
+
+	c.mu.Lock()
+	c.closed = true
+	releasers := c.releasers
+	c.mu.Unlock()
+
+	if c.drainTimeout > 0 {
+		drained := make(chan struct{})
+		go func() {
+			c.inflight.Wait()
+			close(drained)
+		}()
+
+		timer := time.NewTimer(c.drainTimeout)
+		select {
+		case <-drained:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+
+	c.cancel()
+	c.background.Wait()
+	c.client.CloseIdleConnections()
+
+	for _, release := range releasers {
+		release()
+	}
+
+	return nil
+}
@@ -2,28 +2,91 @@ package reqx
 
 import (
 	"context"
-	"encoding/base64"
+	"log/slog"
+	"maps"
 	"net/http"
-	"strings"
+	"net/http/cookiejar"
 	"time"
 )
 
 type ClientBuilder struct {
-	context     context.Context
-	baseUrl     string
-	timeout     time.Duration
-	queryParams map[string]string
-	headers     map[string]string
-	contentType ContentType
-	oauth1      *OAuth1Config
-	retryConfig *RetryConfig
+	context      context.Context
+	baseUrl      string
+	timeout      time.Duration
+	queryParams  map[string]string
+	headers      map[string]string
+	contentType  ContentType
+	oauth1       *OAuth1Config
+	retryConfig  *RetryConfig
+	drainTimeout time.Duration
+	successCodes []int
+	rateLimits   bool
+	conditional  bool
+	variables    map[string]string
+	envVariables map[string]bool
+	deadline     *deadlineHeader
+	transport    *http.Transport
+	phases       [phaseCount][]RequestMiddleware
+	transformers []ResponseTransformer
+
+	attemptHeader     string
+	idempotencyHeader string
+	requestIDHeader   string
+	shadow            *ShadowConfig
+	bodyTransformers  []BodyTransformer
+	middlewares       []Middleware
+	pathLimits        []*pathRateLimit
+	hooks             hooks
+	throttle          *adaptiveThrottle
+	quotas            quotas
+	auth              AuthProvider
+	firewall          *firewall
+	redactedHeaders   []string
+	redactedParams    []string
+	allowedHeaders    map[string]bool
+	urlCredentials    URLCredentialsMode
+	decodedCache      *decodedCache
+	retryRules        []RetryRule
+	srvTTL            time.Duration
+	staticHosts       map[string]string
+	trafficDump       *trafficDump
+	idGenerator       IDGenerator
+	idempotentRetries bool
+	canary            *canary
+	replayLimit       int64
+	connectTimeout    time.Duration
+	bodyReadTimeout   time.Duration
+	errorHandler      ErrorHandler
+	fallback          Fallback
+	httpCache         *httpCache
+	tokenExpired      func(resp *Response) bool
+	hashBodies        bool
+	traceFormats      []TraceFormat
+	traceSource       TraceSource
+	failover          []string
+	propagatedHeaders []string
+	breaker           *circuitBreaker
+	limiter           Limiter
+	maxConcurrent     int
+	queueTimeout      time.Duration
+	capabilities      Capability
+	metrics           MetricsCollector
+	debug             bool
+	debugBodyLimit    int64
+	har               *HARRecorder
+	logger            *slog.Logger
+	logLevel          slog.Leveler
 }
 
 func NewClientBuilder() *ClientBuilder {
 	return &ClientBuilder{
-		context:     context.Background(),
-		queryParams: make(map[string]string),
-		headers:     make(map[string]string),
+		context:      context.Background(),
+		queryParams:  make(map[string]string),
+		headers:      make(map[string]string),
+		variables:    make(map[string]string),
+		transport:    http.DefaultTransport.(*http.Transport).Clone(),
+		replayLimit:  defaultReplayLimit,
+		capabilities: DefaultCapabilities,
 		retryConfig: &RetryConfig{
 			MaxRetries: 3,
 			BackoffMs:  1000,
@@ -57,29 +120,11 @@ func (c *ClientBuilder) MultipartFormContentType() *ClientBuilder {
 }
 
 func (h *ClientBuilder) BasicAuth(username string, password string) *ClientBuilder {
-	var credBuilder strings.Builder
-	credBuilder.WriteString(username)
-	credBuilder.WriteString(":")
-	credBuilder.WriteString(password)
-	credentials := credBuilder.String()
-
-	var authBuilder strings.Builder
-	authBuilder.WriteString("Basic ")
-	authBuilder.WriteString(base64.StdEncoding.EncodeToString([]byte(credentials)))
-
-	h.headers["Authorization"] = authBuilder.String()
-
-	return h
+	return h.Auth(BasicAuthProvider{Username: username, Password: password})
 }
 
 func (h *ClientBuilder) BearerAuth(token string) *ClientBuilder {
-	var builder strings.Builder
-	builder.WriteString("Bearer ")
-	builder.WriteString(token)
-
-	h.headers["Authorization"] = builder.String()
-
-	return h
+	return h.Auth(BearerAuthProvider{Token: token})
 }
 
 func (h *ClientBuilder) OAuth1(consumerKey, consumerSecret, accessToken, accessTokenSecret string) *ClientBuilder {
@@ -107,24 +152,195 @@ func (h *ClientBuilder) Header(key, value string) *ClientBuilder {
 	return h
 }
 
+func (h *ClientBuilder) OAuth1Config(config OAuth1Config) *ClientBuilder {
+	h.oauth1 = &config
+	return h
+}
+
 func (h *ClientBuilder) RetryConfig(maxRetries int, backoffMs int) *ClientBuilder {
-	h.retryConfig = &RetryConfig{
-		MaxRetries: maxRetries,
-		BackoffMs:  backoffMs,
+	h.retryConfig.MaxRetries = maxRetries
+	h.retryConfig.BackoffMs = backoffMs
+	return h
+}
+
+func (h *ClientBuilder) SuccessStatuses(codes ...int) *ClientBuilder {
+	h.successCodes = append(h.successCodes, codes...)
+	return h
+}
+
+func (h *ClientBuilder) Variable(name, value string) *ClientBuilder {
+	h.variables[name] = value
+	return h
+}
+
+func (h *ClientBuilder) Variables(variables map[string]string) *ClientBuilder {
+	for name, value := range variables {
+		h.variables[name] = value
 	}
 	return h
 }
 
+func (h *ClientBuilder) EnvVariables(names ...string) *ClientBuilder {
+	if h.envVariables == nil {
+		h.envVariables = make(map[string]bool)
+	}
+	for _, name := range names {
+		h.envVariables[name] = true
+	}
+	return h
+}
+
+func (h *ClientBuilder) DeadlineHeader(name string, format DeadlineFormat) *ClientBuilder {
+	h.deadline = &deadlineHeader{name: name, format: format}
+	return h
+}
+
+func (h *ClientBuilder) DisableDecompression() *ClientBuilder {
+	h.capabilities &^= CapDecompression
+	return h
+}
+
+func (h *ClientBuilder) AttemptHeader(name string) *ClientBuilder {
+	h.attemptHeader = name
+	return h
+}
+
+func (h *ClientBuilder) IdempotencyKeyHeader(name string) *ClientBuilder {
+	h.idempotencyHeader = name
+	return h
+}
+
+func (h *ClientBuilder) RequestIDHeader(name string) *ClientBuilder {
+	h.requestIDHeader = name
+	return h
+}
+
+func (h *ClientBuilder) RateLimitAware() *ClientBuilder {
+	h.rateLimits = true
+	return h
+}
+
+func (h *ClientBuilder) ConditionalRequests() *ClientBuilder {
+	h.conditional = true
+	return h
+}
+
+func (h *ClientBuilder) GitHubPreset() *ClientBuilder {
+	return h.RateLimitAware().ConditionalRequests()
+}
+
+func (h *ClientBuilder) DrainTimeout(timeout time.Duration) *ClientBuilder {
+	h.drainTimeout = timeout
+	return h
+}
+
 func (h *ClientBuilder) Build() *Client {
-	return &Client{
-		context:     h.context,
-		client:      &http.Client{Timeout: h.timeout},
-		baseUrl:     h.baseUrl,
-		timeout:     h.timeout,
-		queryParams: h.queryParams,
-		headers:     h.headers,
-		contentType: h.contentType,
-		oauth1:      h.oauth1,
-		retryConfig: h.retryConfig,
+	ctx, cancel := context.WithCancel(h.context)
+
+	var conditional *conditionalCache
+	if h.conditional {
+		conditional = newConditionalCache()
+	}
+
+	transport := h.transport.Clone()
+	if h.connectTimeout > 0 {
+		transport.DialContext = connectTimeoutDialer(h.connectTimeout, transport.DialContext)
+	}
+	if len(h.staticHosts) > 0 {
+		transport.DialContext = staticHostsDialer(maps.Clone(h.staticHosts), transport.DialContext)
+	}
+
+	if !h.capabilities.Has(CapDecompression) {
+		transport.DisableCompression = true
+	}
+	if h.firewall != nil {
+		// A proxy resolves and dials the target itself, out of reach of
+		// the firewall's dial-time checks.
+		transport.Proxy = nil
+	}
+
+	// Builder setters keep changing the builder's copy after Build.
+	retryConfig := *h.retryConfig
+
+	httpClient := &http.Client{Timeout: h.timeout, Transport: transport, CheckRedirect: h.checkRedirect()}
+	if h.capabilities.Has(CapCookies) {
+		httpClient.Jar, _ = cookiejar.New(nil)
+	}
+
+	client := &Client{
+		context:      ctx,
+		client:       httpClient,
+		baseUrl:      h.baseUrl,
+		timeout:      h.timeout,
+		queryParams:  h.queryParams,
+		headers:      h.headers,
+		contentType:  h.contentType,
+		oauth1:       h.oauth1,
+		retryConfig:  &retryConfig,
+		successCodes: h.successCodes,
+		rateLimits:   newRateLimitTracker(h.rateLimits),
+		conditional:  conditional,
+		variables:    h.variables,
+		envVariables: h.envVariables,
+		deadline:     h.deadline,
+		phases:       h.phases,
+		transformers: h.transformers,
+
+		attemptHeader:     h.attemptHeader,
+		idempotencyHeader: h.idempotencyHeader,
+		requestIDHeader:   h.requestIDHeader,
+		shadow:            h.shadow,
+		bodyTransformers:  h.bodyTransformers,
+		middlewares:       h.middlewares,
+		pathLimits:        h.pathLimits,
+		hooks:             h.hooks,
+		throttle:          h.throttle,
+		quotas:            h.quotas,
+		auth:              h.auth,
+		firewall:          h.firewall,
+		redactedHeaders:   h.redactedHeaders,
+		redactedParams:    h.redactedParams,
+		allowedHeaders:    h.allowedHeaders,
+		urlCredentials:    h.urlCredentials,
+		decodedCache:      h.decodedCache,
+		retryRules:        h.retryRules,
+		srv:               newSRVResolver(h.srvTTL),
+		trafficDump:       h.trafficDump,
+		idGenerator:       h.idGenerator,
+		idempotentRetries: h.idempotentRetries,
+		canary:            h.canary,
+		replayLimit:       h.replayLimit,
+		bodyReadTimeout:   h.bodyReadTimeout,
+		errorHandler:      h.errorHandler,
+		fallback:          h.fallback,
+		tokenExpired:      h.tokenExpired,
+		hashBodies:        h.hashBodies,
+		traceFormats:      h.traceFormats,
+		traceSource:       h.traceSource,
+		capabilities:      h.capabilities,
+		metrics:           h.metrics,
+		refreshes:         newRefreshGroup(ctx),
+		debug:             h.debug,
+		debugBodyLimit:    h.debugBodyLimit,
+		har:               h.har,
+		logger:            h.buildLogger(),
+		logLevel:          h.logLevel,
+		failover:          h.failover,
+		propagatedHeaders: h.propagatedHeaders,
+		breaker:           h.breaker,
+		limiter:           h.limiter,
+		bulkhead:          newBulkhead(h.maxConcurrent, h.queueTimeout),
+		cancel:            cancel,
+		drainTimeout:      h.drainTimeout,
+		cancels:           make(map[uint64]context.CancelCauseFunc),
+	}
+	if h.httpCache != nil {
+		client.httpCache = h.httpCache.forClient(ctx)
+		client.onRelease(client.httpCache.wait)
 	}
+	if h.trafficDump != nil {
+		client.onRelease(h.trafficDump.close)
+	}
+
+	return client
 }
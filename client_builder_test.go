@@ -0,0 +1,28 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBuiltClientKeepsItsRetryConfig(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	builder := NewClientBuilder().BaseUrl(server.URL).RetryConfig(0, 1)
+	client := builder.Build()
+	defer client.Close()
+
+	builder.RetryConfig(3, 1).RetryBackoff(BackoffExponential, 10, JitterNone)
+
+	client.Get("/").DoRaw()
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1 as configured when the client was built", got)
+	}
+}
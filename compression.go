@@ -0,0 +1,33 @@
+package reqx
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func (r *Response) DecompressedBody() ([]byte, error) {
+	reader, err := decompressReader(r.ContentEncoding, bytes.NewReader(r.Body))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	return io.ReadAll(reader)
+}
+
+func decompressReader(encoding string, reader io.Reader) (io.ReadCloser, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "", "identity":
+		return io.NopCloser(reader), nil
+	case "gzip", "x-gzip":
+		return gzip.NewReader(reader)
+	case "deflate":
+		return flate.NewReader(reader), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+}
@@ -0,0 +1,102 @@
+package reqx
+
+import (
+	"net/http"
+	"sync"
+)
+
+const maxConditionalEntries = 1024
+
+type conditionalEntry struct {
+	etag         string
+	lastModified string
+	status       int
+	headers      http.Header
+	body         []byte
+}
+
+type conditionalCache struct {
+	mu      sync.Mutex
+	entries map[string]*conditionalEntry
+}
+
+func newConditionalCache() *conditionalCache {
+	return &conditionalCache{
+		entries: make(map[string]*conditionalEntry),
+	}
+}
+
+func (c *conditionalCache) prepare(req *http.Request) {
+	if req.Method != http.MethodGet {
+		return
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[req.URL.String()]
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	if entry.etag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	if entry.lastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", entry.lastModified)
+	}
+}
+
+func (c *conditionalCache) resolve(req *http.Request, response *Response) *Response {
+	if req.Method != http.MethodGet {
+		return response
+	}
+
+	key := req.URL.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if response.Status == http.StatusNotModified {
+		entry, ok := c.entries[key]
+		if !ok {
+			return response
+		}
+
+		headers := entry.headers.Clone()
+		for k, v := range response.Headers {
+			headers[k] = v
+		}
+
+		return &Response{
+			Status:       entry.status,
+			Body:         entry.body,
+			Headers:      headers,
+			FromCache:    true,
+			Timings:      response.Timings,
+			successCodes: response.successCodes,
+		}
+	}
+
+	etag := response.Headers.Get("ETag")
+	lastModified := response.Headers.Get("Last-Modified")
+	if response.Status != http.StatusOK || (etag == "" && lastModified == "") {
+		return response
+	}
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxConditionalEntries {
+		for k := range c.entries {
+			delete(c.entries, k)
+			break
+		}
+	}
+
+	c.entries[key] = &conditionalEntry{
+		etag:         etag,
+		lastModified: lastModified,
+		status:       response.Status,
+		headers:      response.Headers.Clone(),
+		body:         response.Body,
+	}
+
+	return response
+}
@@ -0,0 +1,48 @@
+package reqx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+func (h *ClientBuilder) HashBodies() *ClientBuilder {
+	h.hashBodies = true
+	return h
+}
+
+func contentHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+type ContentTracker struct {
+	mu     sync.Mutex
+	hashes map[string]string
+}
+
+func NewContentTracker() *ContentTracker {
+	return &ContentTracker{hashes: make(map[string]string)}
+}
+
+// The first response seen for a key counts as changed.
+func (t *ContentTracker) Changed(key string, resp *Response) bool {
+	hash := resp.ContentHash
+	if hash == "" {
+		hash = contentHash(resp.Body)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	previous, seen := t.hashes[key]
+	t.hashes[key] = hash
+	return !seen || previous != hash
+}
+
+func (t *ContentTracker) Forget(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.hashes, key)
+}
@@ -0,0 +1,40 @@
+package reqx
+
+import (
+	"net/url"
+)
+
+type URLCredentialsMode int
+
+const (
+	URLCredentialsBasicAuth URLCredentialsMode = iota
+	URLCredentialsStrip
+)
+
+func (h *ClientBuilder) URLCredentials(mode URLCredentialsMode) *ClientBuilder {
+	h.urlCredentials = mode
+	return h
+}
+
+func (c *RequestBuilder) stripURLCredentials(fullURL string) (string, AuthProvider) {
+	u, err := url.Parse(fullURL)
+	if err != nil || u.User == nil {
+		return fullURL, nil
+	}
+
+	userinfo := u.User
+	u.User = nil
+	stripped := u.String()
+
+	if c.client.urlCredentials == URLCredentialsStrip {
+		c.log().Warn("stripped credentials from request URL",
+			"package", "reqx",
+			"method", string(c.method),
+			"url", c.client.redactURL(stripped),
+		)
+		return stripped, nil
+	}
+
+	password, _ := userinfo.Password()
+	return stripped, BasicAuthProvider{Username: userinfo.Username(), Password: password}
+}
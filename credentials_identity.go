@@ -0,0 +1,79 @@
+package reqx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+type credentialIdentityKey struct{}
+
+// Closures made from one function literal share a code pointer, so a
+// function set as a single request's provider cannot be told apart from
+// another user's. Such requests bypass the response caches.
+const unidentifiedCredentials = "unidentified"
+
+// credentialIdentity names the credentials a request is sent with, based on
+// the configuration rather than the header on the wire. OAuth1 nonces and
+// rotating tokens from a token source therefore keep one identity, which
+// keeps per-credential state such as rate-limit windows and cache entries
+// bounded.
+func (b *RequestBuilder) credentialIdentity(provider AuthProvider) string {
+	if b.auth != nil && !b.noAuth && reflect.ValueOf(b.auth).Kind() == reflect.Func {
+		return unidentifiedCredentials
+	}
+
+	var parts []string
+	if provider != nil {
+		parts = append(parts, describeProvider(provider))
+	}
+	if b.client.oauth1 != nil && !b.noAuth {
+		parts = append(parts, "oauth1:"+b.client.oauth1.ConsumerKey+":"+b.client.oauth1.AccessToken)
+	}
+	for name, value := range b.client.headers {
+		if !b.noAuth && b.isCredentialHeader(name) {
+			parts = append(parts, "client:"+strings.ToLower(name)+":"+value)
+		}
+	}
+	for name, value := range b.headers {
+		if b.isCredentialHeader(name) {
+			parts = append(parts, "request:"+strings.ToLower(name)+":"+value)
+		}
+	}
+	for name, values := range b.forwarded {
+		if b.isCredentialHeader(name) {
+			parts = append(parts, "forwarded:"+strings.ToLower(name)+":"+strings.Join(values, ","))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	slices.Sort(parts)
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\n")))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Providers holding state behind a pointer, such as token sources, are
+// identified by that pointer instead of their changing contents.
+func describeProvider(provider AuthProvider) string {
+	switch reflect.ValueOf(provider).Kind() {
+	case reflect.Pointer, reflect.Func, reflect.Map, reflect.Chan:
+		return fmt.Sprintf("%T:%p", provider, provider)
+	default:
+		return fmt.Sprintf("%T:%+v", provider, provider)
+	}
+}
+
+func withCredentialIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, credentialIdentityKey{}, identity)
+}
+
+func credentialIdentityFrom(ctx context.Context) string {
+	identity, _ := ctx.Value(credentialIdentityKey{}).(string)
+	return identity
+}
@@ -0,0 +1,174 @@
+package reqx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type CronSchedule struct {
+	minute     []bool
+	hour       []bool
+	day        []bool
+	month      []bool
+	weekday    []bool
+	anyDay     bool
+	anyWeekday bool
+}
+
+func ParseCron(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = macro
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: %q: expected 5 fields", ErrInvalidCron, expr)
+	}
+
+	schedule := &CronSchedule{
+		anyDay:     fields[2] == "*",
+		anyWeekday: fields[4] == "*",
+	}
+	bounds := []struct {
+		target   *[]bool
+		min, max int
+	}{
+		{&schedule.minute, 0, 59},
+		{&schedule.hour, 0, 23},
+		{&schedule.day, 1, 31},
+		{&schedule.month, 1, 12},
+		{&schedule.weekday, 0, 7},
+	}
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i].min, bounds[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %v", ErrInvalidCron, expr, err)
+		}
+		*bounds[i].target = set
+	}
+
+	// Both 0 and 7 mean Sunday.
+	schedule.weekday[0] = schedule.weekday[0] || schedule.weekday[7]
+	return schedule, nil
+}
+
+func parseCronField(field string, min, max int) ([]bool, error) {
+	set := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangeSpec, stepSpec, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepSpec)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		start, end := min, max
+		switch {
+		case rangeSpec == "*":
+		case strings.Contains(rangeSpec, "-"):
+			first, last, _ := strings.Cut(rangeSpec, "-")
+			var err error
+			if start, err = parseCronValue(first, min, max); err != nil {
+				return nil, err
+			}
+			if end, err = parseCronValue(last, min, max); err != nil {
+				return nil, err
+			}
+			if start > end {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			value, err := parseCronValue(rangeSpec, min, max)
+			if err != nil {
+				return nil, err
+			}
+			start = value
+			if !hasStep {
+				end = value
+			}
+		}
+
+		for value := start; value <= end; value += step {
+			set[value] = true
+		}
+	}
+
+	return set, nil
+}
+
+func parseCronValue(value string, min, max int) (int, error) {
+	n, ok := cronNames[strings.ToLower(value)]
+	if !ok {
+		var err error
+		if n, err = strconv.Atoi(value); err != nil {
+			return 0, fmt.Errorf("invalid value %q", value)
+		}
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", n, min, max)
+	}
+
+	return n, nil
+}
+
+// Next returns the first matching minute after t, or the zero time if none
+// occurs within five years. As in classic cron, a restricted day of month
+// and day of week match when either does.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case !s.month[t.Month()]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !s.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !s.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	day, weekday := s.day[t.Day()], s.weekday[t.Weekday()]
+	switch {
+	case s.anyDay && s.anyWeekday:
+		return true
+	case s.anyDay:
+		return weekday
+	case s.anyWeekday:
+		return day
+	default:
+		return day || weekday
+	}
+}
@@ -0,0 +1,100 @@
+package reqx
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// AsCurl renders the request as a curl command line, as it would be sent on
+// the first attempt: templates, headers, auth and body transformers are all
+// applied. Credentials are included verbatim so the command reproduces the
+// call. A streamed body that cannot be replayed is consumed.
+func (c *RequestBuilder) AsCurl() (string, error) {
+	ctx := c.context
+	if ctx == nil {
+		ctx = c.client.context
+	}
+
+	rawURL := c.buildUrl()
+	resolvedURL, err := c.client.srv.rewrite(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	url, urlAuth := c.stripURLCredentials(resolvedURL)
+	req, err := c.buildRequest(ctx, url, urlAuth)
+	if err != nil {
+		return "", err
+	}
+	if err := c.authorizeRequest(req, urlAuth); err != nil {
+		return "", err
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		c.rewindBody()
+	}
+
+	args := []string{"curl"}
+	if req.Method != http.MethodGet || len(body) > 0 {
+		args = append(args, "-X", req.Method)
+	}
+	args = append(args, shellQuote(req.URL.String()))
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			args = append(args, "-H", shellQuote(name+": "+value))
+		}
+	}
+	if req.Host != "" && req.Host != req.URL.Host {
+		args = append(args, "-H", shellQuote("Host: "+req.Host))
+	}
+
+	if len(body) > 0 {
+		args = append(args, "--data-binary", shellQuote(string(body)))
+	}
+
+	return strings.Join(args, " "), nil
+}
+
+// Text is single-quoted for any POSIX shell; anything else falls back to
+// ANSI-C quoting, which bash and zsh understand.
+func shellQuote(s string) string {
+	if utf8.ValidString(s) && !strings.ContainsFunc(s, isControl) {
+		return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+	}
+
+	var quoted strings.Builder
+	quoted.WriteString("$'")
+	for i := 0; i < len(s); i++ {
+		switch b := s[i]; {
+		case b == '\'' || b == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(b)
+		case b < 0x20 || b >= 0x7f:
+			fmt.Fprintf(&quoted, `\x%02x`, b)
+		default:
+			quoted.WriteByte(b)
+		}
+	}
+	quoted.WriteString("'")
+	return quoted.String()
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 && r != '\n' && r != '\t' || r == 0x7f
+}
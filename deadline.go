@@ -0,0 +1,67 @@
+package reqx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type DeadlineFormat int
+
+const (
+	DeadlineFormatMillis DeadlineFormat = iota
+	DeadlineFormatGRPC
+)
+
+type deadlineHeader struct {
+	name   string
+	format DeadlineFormat
+}
+
+func (d *deadlineHeader) apply(ctx context.Context, req *http.Request) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return
+	}
+
+	remaining := time.Until(deadline)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	switch d.format {
+	case DeadlineFormatGRPC:
+		req.Header.Set(d.name, formatGRPCTimeout(remaining))
+	default:
+		req.Header.Set(d.name, strconv.FormatInt(remaining.Milliseconds(), 10))
+	}
+}
+
+func formatGRPCTimeout(timeout time.Duration) string {
+	const maxValue = 99999999
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"n", time.Nanosecond},
+		{"u", time.Microsecond},
+		{"m", time.Millisecond},
+		{"S", time.Second},
+		{"M", time.Minute},
+		{"H", time.Hour},
+	}
+
+	for _, unit := range units {
+		value := timeout / unit.size
+		if timeout%unit.size != 0 {
+			value++
+		}
+		if value <= maxValue {
+			return strconv.FormatInt(int64(value), 10) + unit.suffix
+		}
+	}
+
+	return strconv.Itoa(maxValue) + "H"
+}
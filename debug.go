@@ -0,0 +1,144 @@
+package reqx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+	"unicode/utf8"
+)
+
+const defaultDebugBodyBytes = 64 << 10
+
+type debugLoggerKey struct{}
+
+func (h *ClientBuilder) Debug(enabled bool) *ClientBuilder {
+	h.debug = enabled
+	return h
+}
+
+// DebugBodyLimit caps how much of each body a debug dump shows. A negative
+// limit leaves bodies out entirely.
+func (h *ClientBuilder) DebugBodyLimit(limit int64) *ClientBuilder {
+	h.debugBodyLimit = limit
+	return h
+}
+
+func (c *RequestBuilder) Debug() *RequestBuilder {
+	c.debug = true
+	return c
+}
+
+func (c *RequestBuilder) withDebug(req *http.Request) *http.Request {
+	if !c.debug && !c.client.debug {
+		return req
+	}
+	return req.WithContext(context.WithValue(req.Context(), debugLoggerKey{}, c.log()))
+}
+
+// dumpDebug sits below the HTTP cache, so only requests that reach the
+// network are dumped. Responses are logged once their body has been read.
+func (c *Client) dumpDebug(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		logger, ok := req.Context().Value(debugLoggerKey{}).(*slog.Logger)
+		if !ok {
+			return next(req)
+		}
+
+		limit := c.debugBodyLimit
+		if limit == 0 {
+			limit = defaultDebugBodyBytes
+		}
+
+		var body []byte
+		truncated := false
+		if req.GetBody != nil && limit > 0 {
+			if reader, err := req.GetBody(); err == nil {
+				body, truncated = readDebugBody(reader, limit)
+				reader.Close()
+			}
+		}
+		logger.Info("reqx debug request",
+			"package", "reqx",
+			"dump", c.debugDump(fmt.Sprintf("%s %s %s", req.Method, c.redactURL(req.URL.String()), req.Proto), req.Header, body, truncated),
+		)
+
+		started := time.Now()
+		resp, err := next(req)
+		if err != nil {
+			logger.Info("reqx debug response",
+				"package", "reqx",
+				"duration", time.Since(started),
+				"error", c.redactError(err),
+			)
+			return resp, err
+		}
+
+		status := fmt.Sprintf("%s %s", resp.Proto, resp.Status)
+		if limit < 0 {
+			logger.Info("reqx debug response",
+				"package", "reqx",
+				"duration", time.Since(started),
+				"dump", c.debugDump(status, resp.Header, nil, false),
+			)
+			return resp, nil
+		}
+
+		resp.Body = &dumpBody{
+			ReadCloser: resp.Body,
+			limit:      limit,
+			done: func(body []byte, truncated bool) {
+				logger.Info("reqx debug response",
+					"package", "reqx",
+					"duration", time.Since(started),
+					"dump", c.debugDump(status, resp.Header, body, truncated),
+				)
+			},
+		}
+		return resp, nil
+	}
+}
+
+func (c *Client) debugDump(firstLine string, header http.Header, body []byte, truncated bool) string {
+	var dump bytes.Buffer
+	dump.WriteString(firstLine)
+	dump.WriteString("\n")
+
+	headers := c.redactHeaders(header)
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			fmt.Fprintf(&dump, "%s: %s\n", name, value)
+		}
+	}
+
+	if len(body) > 0 {
+		dump.WriteString("\n")
+		if utf8.Valid(body) {
+			dump.Write(body)
+		} else {
+			fmt.Fprintf(&dump, "<%d bytes of binary data>", len(body))
+		}
+		if truncated {
+			dump.WriteString("\n<truncated>")
+		}
+	}
+
+	return dump.String()
+}
+
+func readDebugBody(body io.Reader, limit int64) ([]byte, bool) {
+	data, _ := io.ReadAll(io.LimitReader(body, limit+1))
+	if int64(len(data)) > limit {
+		return data[:limit], true
+	}
+	return data, false
+}
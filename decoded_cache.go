@@ -0,0 +1,184 @@
+package reqx
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+type decodedCacheKey struct {
+	request string
+	target  reflect.Type
+}
+
+type decodedCacheEntry struct {
+	value    reflect.Value
+	response Response
+	stored   time.Time
+}
+
+type decodedCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[decodedCacheKey]*decodedCacheEntry
+}
+
+func (h *ClientBuilder) DecodedCache(ttl time.Duration, maxEntries int) *ClientBuilder {
+	h.decodedCache = &decodedCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[decodedCacheKey]*decodedCacheEntry),
+	}
+	return h
+}
+
+func (c *RequestBuilder) NoDecodedCache() *RequestBuilder {
+	c.skipDecodedCache = true
+	return c
+}
+
+func (c *RequestBuilder) decodedCacheKey(successTarget any) (decodedCacheKey, bool) {
+	target := reflect.ValueOf(successTarget)
+	if c.client.decodedCache == nil || c.skipDecodedCache || c.tee != nil || c.method != MethodGet ||
+		target.Kind() != reflect.Pointer || target.IsNil() {
+		return decodedCacheKey{}, false
+	}
+
+	identity := c.credentialIdentity(c.authProvider())
+	if identity == unidentifiedCredentials {
+		return decodedCacheKey{}, false
+	}
+
+	headers := maps.Clone(c.client.headers)
+	maps.Copy(headers, c.headers)
+
+	var builder strings.Builder
+	builder.WriteString(c.buildUrl())
+	builder.WriteString("\nauth: ")
+	builder.WriteString(identity)
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		builder.WriteString("\n")
+		builder.WriteString(strings.ToLower(name))
+		builder.WriteString(": ")
+		builder.WriteString(headers[name])
+	}
+
+	return decodedCacheKey{request: builder.String(), target: target.Type()}, true
+}
+
+func (d *decodedCache) load(key decodedCacheKey, successTarget any) (*Response, bool) {
+	d.mu.Lock()
+	entry, ok := d.entries[key]
+	if ok && time.Since(entry.stored) >= d.ttl {
+		delete(d.entries, key)
+		ok = false
+	}
+	d.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	reflect.ValueOf(successTarget).Elem().Set(deepCopy(entry.value))
+
+	response := entry.response
+	response.Headers = entry.response.Headers.Clone()
+	response.Body = slices.Clone(entry.response.Body)
+	response.FromCache = true
+	return &response, true
+}
+
+func (d *decodedCache) store(key decodedCacheKey, successTarget any, response *Response) {
+	entry := &decodedCacheEntry{
+		value: deepCopy(reflect.ValueOf(successTarget).Elem()),
+		response: Response{
+			Status:       response.Status,
+			Body:         slices.Clone(response.Body),
+			Headers:      response.Headers.Clone(),
+			ContentHash:  response.ContentHash,
+			successCodes: response.successCodes,
+		},
+		stored: time.Now(),
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.entries[key] = entry
+	if d.maxEntries > 0 && len(d.entries) > d.maxEntries {
+		d.evict()
+	}
+}
+
+func (d *decodedCache) evict() {
+	var oldestKey decodedCacheKey
+	var oldest time.Time
+	for key, entry := range d.entries {
+		if time.Since(entry.stored) >= d.ttl {
+			delete(d.entries, key)
+			continue
+		}
+		if oldest.IsZero() || entry.stored.Before(oldest) {
+			oldestKey, oldest = key, entry.stored
+		}
+	}
+
+	if len(d.entries) > d.maxEntries {
+		delete(d.entries, oldestKey)
+	}
+}
+
+func deepCopy(src reflect.Value) reflect.Value {
+	dst := reflect.New(src.Type()).Elem()
+
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return dst
+		}
+		dst.Set(reflect.New(src.Elem().Type()))
+		dst.Elem().Set(deepCopy(src.Elem()))
+	case reflect.Interface:
+		if src.IsNil() {
+			return dst
+		}
+		dst.Set(deepCopy(src.Elem()))
+	case reflect.Slice:
+		if src.IsNil() {
+			return dst
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+	case reflect.Map:
+		if src.IsNil() {
+			return dst
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+	case reflect.Array:
+		for i := range src.Len() {
+			dst.Index(i).Set(deepCopy(src.Index(i)))
+		}
+	case reflect.Struct:
+		dst.Set(src)
+		for i := range src.NumField() {
+			if dst.Field(i).CanSet() {
+				dst.Field(i).Set(deepCopy(src.Field(i)))
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+
+	return dst
+}
@@ -0,0 +1,53 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDecodedCacheSeparatesCredentials(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user":"` + r.Header.Get("Authorization") + `"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).DecodedCache(time.Minute, 16).Build()
+	defer client.Close()
+
+	as := func(user string) AuthProvider {
+		return AuthFunc(func(req *http.Request) error {
+			req.Header.Set("Authorization", user)
+			return nil
+		})
+	}
+
+	for _, user := range []string{"alice", "bob"} {
+		var me struct{ User string }
+		if _, err := client.Get("/me").Auth(as(user)).Do(&me, nil); err != nil {
+			t.Fatal(err)
+		}
+		if me.User != user {
+			t.Errorf("%s got %q from the decoded cache", user, me.User)
+		}
+	}
+
+	hits.Store(0)
+	for _, token := range []string{"alice", "bob", "alice", "bob"} {
+		var me struct{ User string }
+		if _, err := client.Get("/me").BearerAuth(token).Do(&me, nil); err != nil {
+			t.Fatal(err)
+		}
+		if me.User != "Bearer "+token {
+			t.Errorf("%s got %q from the decoded cache", token, me.User)
+		}
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server hits = %d, want one per token", hits.Load())
+	}
+}
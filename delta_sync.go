@@ -0,0 +1,290 @@
+package reqx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type SyncState struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Cursor       string    `json:"cursor,omitempty"`
+	SyncedAt     time.Time `json:"synced_at"`
+}
+
+type SyncStore interface {
+	Load(ctx context.Context, key string) (*SyncState, error)
+	Save(ctx context.Context, key string, state *SyncState) error
+}
+
+type SyncConfig struct {
+	Key          string
+	Store        SyncStore
+	ItemsPath    string
+	CursorParam  string
+	CursorPath   string
+	CursorHeader string
+}
+
+type SyncResult struct {
+	NotModified bool
+	Pages       int
+	Items       int
+	State       SyncState
+}
+
+type DeltaSync[T any] struct {
+	client *Client
+	path   string
+	config SyncConfig
+
+	mu sync.Mutex
+}
+
+func NewDeltaSync[T any](client *Client, path string, config SyncConfig) *DeltaSync[T] {
+	if config.Key == "" {
+		config.Key = path
+	}
+	if config.Store == nil {
+		config.Store = NewMemorySyncStore()
+	}
+
+	return &DeltaSync[T]{client: client, path: path, config: config}
+}
+
+// The stored state only advances after every page was applied, so a failed
+// run is repeated from the previous cursor.
+func (s *DeltaSync[T]) Run(ctx context.Context, apply func(items []T) error) (*SyncResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, err := s.config.Store.Load(ctx, s.config.Key)
+	if err != nil {
+		return nil, err
+	}
+	if state == nil {
+		state = &SyncState{}
+	}
+
+	rb := s.client.Get(s.path).Context(ctx)
+	if state.Cursor != "" && s.config.CursorParam != "" {
+		rb.QueryParam(s.config.CursorParam, state.Cursor)
+	}
+	if state.ETag != "" {
+		rb.Header("If-None-Match", state.ETag)
+	}
+	if state.LastModified != "" {
+		rb.Header("If-Modified-Since", state.LastModified)
+	}
+
+	resp, err := rb.DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &SyncResult{}
+	next := *state
+	next.SyncedAt = time.Now()
+
+	if resp.IsNotModified() {
+		result.NotModified = true
+		result.State = next
+		return result, s.config.Store.Save(ctx, s.config.Key, &next)
+	}
+
+	next.ETag = resp.Headers.Get("ETag")
+	next.LastModified = resp.Headers.Get("Last-Modified")
+
+	for {
+		items, err := s.items(resp)
+		if err != nil {
+			return result, err
+		}
+		if err := apply(items); err != nil {
+			return result, err
+		}
+		result.Pages++
+		result.Items += len(items)
+
+		link := resp.NextLink()
+		if link == "" {
+			break
+		}
+		if resp, err = s.client.Get(link).Context(ctx).DoRaw(); err != nil {
+			return result, err
+		}
+	}
+
+	cursor, err := s.cursor(resp)
+	if err != nil {
+		return result, err
+	}
+	if cursor != "" {
+		next.Cursor = cursor
+	}
+
+	result.State = next
+	return result, s.config.Store.Save(ctx, s.config.Key, &next)
+}
+
+func (s *DeltaSync[T]) items(resp *Response) ([]T, error) {
+	body := json.RawMessage(resp.Body)
+	if s.config.ItemsPath != "" {
+		raw, ok, err := lookupJSONPath(resp.Body, s.config.ItemsPath)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, nil
+		}
+		body = raw
+	}
+
+	var items []T
+	if len(body) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, &DecodeError{Status: resp.Status, Body: resp.Body, Err: err}
+	}
+	return items, nil
+}
+
+func (s *DeltaSync[T]) cursor(resp *Response) (string, error) {
+	if s.config.CursorHeader != "" {
+		if cursor := resp.Headers.Get(s.config.CursorHeader); cursor != "" {
+			return cursor, nil
+		}
+	}
+	if s.config.CursorPath == "" {
+		return "", nil
+	}
+
+	raw, ok, err := lookupJSONPath(resp.Body, s.config.CursorPath)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	var cursor any
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return "", &DecodeError{Status: resp.Status, Body: resp.Body, Err: err}
+	}
+	switch cursor := cursor.(type) {
+	case string:
+		return cursor, nil
+	case nil:
+		return "", nil
+	default:
+		return string(raw), nil
+	}
+}
+
+type memorySyncStore struct {
+	mu     sync.Mutex
+	states map[string]SyncState
+}
+
+func NewMemorySyncStore() SyncStore {
+	return &memorySyncStore{states: make(map[string]SyncState)}
+}
+
+func (s *memorySyncStore) Load(ctx context.Context, key string) (*SyncState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &state, nil
+}
+
+func (s *memorySyncStore) Save(ctx context.Context, key string, state *SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[key] = *state
+	return nil
+}
+
+type fileSyncStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSyncStore(path string) SyncStore {
+	return &fileSyncStore{path: path}
+}
+
+func (s *fileSyncStore) Load(ctx context.Context, key string) (*SyncState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	state, ok := states[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &state, nil
+}
+
+func (s *fileSyncStore) Save(ctx context.Context, key string, state *SyncState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states, err := s.read()
+	if err != nil {
+		return err
+	}
+	states[key] = *state
+
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileSyncStore) read() (map[string]SyncState, error) {
+	states := make(map[string]SyncState)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
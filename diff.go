@@ -0,0 +1,249 @@
+package reqx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type DiffOptions struct {
+	IgnoreHeaders []string
+	IgnorePaths   []string
+}
+
+type Difference struct {
+	Path  string
+	Left  string
+	Right string
+}
+
+type ResponseDiff struct {
+	Differences []Difference
+}
+
+func (d *ResponseDiff) Equal() bool {
+	return len(d.Differences) == 0
+}
+
+func (d *ResponseDiff) String() string {
+	if d.Equal() {
+		return "responses are equal"
+	}
+
+	var builder strings.Builder
+	for _, difference := range d.Differences {
+		builder.WriteString(difference.Path)
+		builder.WriteString(":\n  - ")
+		builder.WriteString(difference.Left)
+		builder.WriteString("\n  + ")
+		builder.WriteString(difference.Right)
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+func DiffResponses(left, right *Response, opts DiffOptions) *ResponseDiff {
+	d := &responseDiffer{
+		ignoreHeaders: make(map[string]bool),
+		ignorePaths:   opts.IgnorePaths,
+	}
+	for _, name := range opts.IgnoreHeaders {
+		d.ignoreHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+
+	if left.Status != right.Status {
+		d.add("status", strconv.Itoa(left.Status), strconv.Itoa(right.Status))
+	}
+
+	d.diffHeaders(left.Headers, right.Headers)
+	d.diffBodies(left.Body, right.Body)
+
+	return &ResponseDiff{Differences: d.differences}
+}
+
+type responseDiffer struct {
+	ignoreHeaders map[string]bool
+	ignorePaths   []string
+	differences   []Difference
+}
+
+func (d *responseDiffer) add(path, left, right string) {
+	d.differences = append(d.differences, Difference{Path: path, Left: left, Right: right})
+}
+
+func (d *responseDiffer) diffHeaders(left, right http.Header) {
+	names := make(map[string]bool)
+	for name := range left {
+		names[http.CanonicalHeaderKey(name)] = true
+	}
+	for name := range right {
+		names[http.CanonicalHeaderKey(name)] = true
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		if d.ignoreHeaders[name] {
+			continue
+		}
+
+		leftValue := strings.Join(left.Values(name), ", ")
+		rightValue := strings.Join(right.Values(name), ", ")
+		if leftValue != rightValue {
+			d.add("header."+name, leftValue, rightValue)
+		}
+	}
+}
+
+func (d *responseDiffer) diffBodies(left, right []byte) {
+	leftValue, leftErr := decodeJSONValue(left)
+	rightValue, rightErr := decodeJSONValue(right)
+	if leftErr != nil || rightErr != nil {
+		if !bytes.Equal(left, right) {
+			d.add("body", bodyExcerpt(left), bodyExcerpt(right))
+		}
+		return
+	}
+
+	d.diffJSON("$", leftValue, rightValue)
+}
+
+func (d *responseDiffer) diffJSON(path string, left, right any) {
+	if d.ignored(path) {
+		return
+	}
+
+	switch l := left.(type) {
+	case map[string]any:
+		r, ok := right.(map[string]any)
+		if !ok {
+			d.add(path, renderJSON(left), renderJSON(right))
+			return
+		}
+
+		keys := make(map[string]bool)
+		for k := range l {
+			keys[k] = true
+		}
+		for k := range r {
+			keys[k] = true
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(keys)) {
+			childPath := path + "." + key
+			leftChild, leftOk := l[key]
+			rightChild, rightOk := r[key]
+
+			switch {
+			case !leftOk:
+				if !d.ignored(childPath) {
+					d.add(childPath, "<missing>", renderJSON(rightChild))
+				}
+			case !rightOk:
+				if !d.ignored(childPath) {
+					d.add(childPath, renderJSON(leftChild), "<missing>")
+				}
+			default:
+				d.diffJSON(childPath, leftChild, rightChild)
+			}
+		}
+	case []any:
+		r, ok := right.([]any)
+		if !ok {
+			d.add(path, renderJSON(left), renderJSON(right))
+			return
+		}
+
+		for i := 0; i < max(len(l), len(r)); i++ {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(l):
+				if !d.ignored(childPath) {
+					d.add(childPath, "<missing>", renderJSON(r[i]))
+				}
+			case i >= len(r):
+				if !d.ignored(childPath) {
+					d.add(childPath, renderJSON(l[i]), "<missing>")
+				}
+			default:
+				d.diffJSON(childPath, l[i], r[i])
+			}
+		}
+	case json.Number:
+		if r, ok := right.(json.Number); ok {
+			leftFloat, leftErr := l.Float64()
+			rightFloat, rightErr := r.Float64()
+			if leftErr == nil && rightErr == nil && leftFloat == rightFloat {
+				return
+			}
+		}
+		d.add(path, renderJSON(left), renderJSON(right))
+	default:
+		leftRendered, rightRendered := renderJSON(left), renderJSON(right)
+		if leftRendered != rightRendered {
+			d.add(path, leftRendered, rightRendered)
+		}
+	}
+}
+
+func (d *responseDiffer) ignored(path string) bool {
+	for _, pattern := range d.ignorePaths {
+		if matchJSONPath(pattern, path) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matchJSONPath(pattern, path string) bool {
+	if !strings.HasPrefix(pattern, "$") {
+		pattern = "$." + strings.TrimPrefix(pattern, ".")
+	}
+
+	for pattern != "" && path != "" {
+		star := strings.Index(pattern, "[*]")
+		if star < 0 {
+			return pattern == path
+		}
+
+		if !strings.HasPrefix(path, pattern[:star+1]) {
+			return false
+		}
+		path = path[star+1:]
+
+		end := strings.IndexByte(path, ']')
+		if end < 0 {
+			return false
+		}
+		path = path[end+1:]
+		pattern = pattern[star+3:]
+	}
+
+	return pattern == path
+}
+
+func decodeJSONValue(body []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func renderJSON(value any) string {
+	rendered, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(rendered)
+}
@@ -0,0 +1,104 @@
+package reqx
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+type Endpoint struct {
+	Method  Method
+	Path    string
+	Success any
+	Error   any
+	Timeout time.Duration
+}
+
+type EndpointParams struct {
+	Path    map[string]string
+	Query   map[string]string
+	Headers map[string]string
+	Body    any
+}
+
+type CallResult struct {
+	*Response
+	Success any
+	Error   any
+}
+
+func (c *Client) Call(endpoint Endpoint, params EndpointParams) (*CallResult, error) {
+	path, err := expandPath(endpoint.Path, params.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	rb := c.NewRequestBuilder().Method(endpoint.Method).Path(path)
+	if endpoint.Timeout > 0 {
+		rb.Timeout(endpoint.Timeout)
+	}
+	for k, v := range params.Query {
+		rb.QueryParam(k, v)
+	}
+	for k, v := range params.Headers {
+		rb.Header(k, v)
+	}
+	if params.Body != nil {
+		rb.Body(params.Body)
+	}
+
+	result := &CallResult{
+		Success: newTarget(endpoint.Success),
+		Error:   newTarget(endpoint.Error),
+	}
+	result.Response, err = rb.Do(result.Success, result.Error)
+
+	return result, err
+}
+
+func expandPath(template string, params map[string]string) (string, error) {
+	var builder strings.Builder
+
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			builder.WriteString(rest)
+			break
+		}
+
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			builder.WriteString(rest)
+			break
+		}
+		end += start
+
+		name := rest[start+1 : end]
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrMissingPathParam, name)
+		}
+
+		builder.WriteString(rest[:start])
+		builder.WriteString(url.PathEscape(value))
+		rest = rest[end+1:]
+	}
+
+	return builder.String(), nil
+}
+
+func newTarget(prototype any) any {
+	if prototype == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(prototype)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return reflect.New(t).Interface()
+}
@@ -0,0 +1,25 @@
+package reqx
+
+func (h *ClientBuilder) ResponseEnvelope(dataPath, errorPath string) *ClientBuilder {
+	return h.TransformResponse(unwrapEnvelope(dataPath, errorPath))
+}
+
+func unwrapEnvelope(dataPath, errorPath string) ResponseTransformer {
+	return func(resp *Response) error {
+		path := dataPath
+		if !resp.IsSuccess() {
+			path = errorPath
+		}
+		if path == "" || len(resp.Body) == 0 {
+			return nil
+		}
+
+		payload, ok, err := lookupJSONPath(resp.Body, path)
+		if err != nil || !ok {
+			return nil
+		}
+
+		resp.Body = payload
+		return nil
+	}
+}
@@ -0,0 +1,85 @@
+package reqx
+
+import (
+	"time"
+)
+
+type ErrorDecision int
+
+const (
+	ErrorRetry ErrorDecision = iota
+	ErrorAbort
+	ErrorFailover
+)
+
+func (d ErrorDecision) String() string {
+	switch d {
+	case ErrorRetry:
+		return "retry"
+	case ErrorAbort:
+		return "abort"
+	case ErrorFailover:
+		return "failover"
+	default:
+		return "unknown"
+	}
+}
+
+type Failure struct {
+	Attempt  int
+	Method   string
+	URL      string
+	Response *Response
+	Err      error
+	Time     time.Time
+}
+
+type ErrorHandler func(failure Failure, decision ErrorDecision) ErrorDecision
+
+func (h *ClientBuilder) ErrorHandler(handler ErrorHandler) *ClientBuilder {
+	h.errorHandler = handler
+	return h
+}
+
+func (h *ClientBuilder) Failover(baseUrls ...string) *ClientBuilder {
+	h.failover = append(h.failover, baseUrls...)
+	return h
+}
+
+// Without a handler, non-retryable error responses are returned as they are;
+// with one, every unsuccessful attempt is offered to it.
+func (r *RequestBuilder) decide(resp *Response, err error, attempt int) (ErrorDecision, bool) {
+	shouldRetry := r.retryable(resp, err, attempt)
+	handler := r.client.errorHandler
+
+	if err == nil && !shouldRetry && (handler == nil || resp.IsSuccess()) {
+		return ErrorAbort, false
+	}
+
+	decision := ErrorAbort
+	if shouldRetry {
+		decision = ErrorRetry
+	}
+	if handler == nil {
+		return decision, true
+	}
+
+	return handler(Failure{
+		Attempt:  attempt,
+		Method:   string(r.method),
+		URL:      r.client.redactURL(r.buildUrl()),
+		Response: resp,
+		Err:      err,
+		Time:     time.Now(),
+	}, decision), true
+}
+
+// Only requests that would go to the client's base URL fail over. The caller
+// must pass a clone, since the base URL is switched in place.
+func (r *RequestBuilder) failoverTargets() []string {
+	if r.baseUrl != "" || isAbsoluteUrl(r.client.expandVariables(r.path)) {
+		return nil
+	}
+
+	return r.client.failover
+}
@@ -2,9 +2,138 @@ package reqx
 
 import (
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 )
 
+const maxBodyExcerpt = 512
+
 var (
-	ErrInvalidBody        = errors.New("reqx.invalid_body")
-	ErrMaxRetriesExceeded = errors.New("reqx.max_retries_exceeded")
+	ErrInvalidBody         = errors.New("reqx.invalid_body")
+	ErrMaxRetriesExceeded  = errors.New("reqx.max_retries_exceeded")
+	ErrClientClosed        = errors.New("reqx.client_closed")
+	ErrCanceled            = errors.New("reqx.canceled")
+	ErrMissingPathParam    = errors.New("reqx.missing_path_param")
+	ErrUnsupportedEncoding = errors.New("reqx.unsupported_encoding")
+	ErrInvalidJSONPath     = errors.New("reqx.invalid_json_path")
+	ErrInvalidJOSE         = errors.New("reqx.invalid_jose")
+	ErrBatcherClosed       = errors.New("reqx.batcher_closed")
+	ErrBatchMismatch       = errors.New("reqx.batch_mismatch")
+	ErrOAuth2State         = errors.New("reqx.oauth2_state_mismatch")
+	ErrTokenExpired        = errors.New("reqx.token_expired")
+	ErrQuotaExceeded       = errors.New("reqx.quota_exceeded")
+	ErrDeniedByPolicy      = errors.New("reqx.denied_by_policy")
+	ErrInvalidHeaderName   = errors.New("reqx.invalid_header_name")
+	ErrInvalidHeaderValue  = errors.New("reqx.invalid_header_value")
+	ErrHeaderNotAllowed    = errors.New("reqx.header_not_allowed")
+	ErrOAuth1PrivateKey    = errors.New("reqx.oauth1_missing_private_key")
+	ErrOAuth1Signature     = errors.New("reqx.oauth1_unsupported_signature_method")
+	ErrOAuth1Callback      = errors.New("reqx.oauth1_callback_not_confirmed")
+	ErrOAuth1Token         = errors.New("reqx.oauth1_missing_token")
+	ErrNotInCluster        = errors.New("reqx.not_in_cluster")
+	ErrInvalidCertificate  = errors.New("reqx.invalid_certificate")
+	ErrBodyTimeout         = errors.New("reqx.body_timeout")
+	ErrMultipartTooLarge   = errors.New("reqx.multipart_too_large")
+	ErrCircuitOpen         = errors.New("reqx.circuit_open")
+	ErrUnsafeArchivePath   = errors.New("reqx.unsafe_archive_path")
+	ErrQueueTimeout        = errors.New("reqx.queue_timeout")
+	ErrSagaFailed          = errors.New("reqx.saga_failed")
+	ErrSagaAborted         = errors.New("reqx.saga_aborted")
+	ErrCacheDisabled       = errors.New("reqx.cache_disabled")
+	ErrCacheSnapshot       = errors.New("reqx.unsupported_cache_snapshot")
+	ErrInvalidContentRange = errors.New("reqx.invalid_content_range")
+	ErrInvalidCron         = errors.New("reqx.invalid_cron")
+	ErrInvalidTraceparent  = errors.New("reqx.invalid_traceparent")
+	ErrCapabilityDisabled  = errors.New("reqx.capability_disabled")
+	ErrJSONPathNotFound    = errors.New("reqx.json_path_not_found")
+	ErrExpectationFailed   = errors.New("reqx.expectation_failed")
 )
+
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("reqx.transport_error: %s %s: %v", e.Method, e.URL, e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+type HTTPError struct {
+	Status int
+	Body   []byte
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("reqx.http_error: status %d", e.Status)
+}
+
+type DecodeError struct {
+	Status int
+	Body   []byte
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("reqx.decode_error: status %d: %v", e.Status, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+type RetryAttempt struct {
+	Attempt int
+	Status  int
+	Err     error
+	Backoff time.Duration
+}
+
+type RetryExhaustedError struct {
+	Attempts    int
+	LastStatus  int
+	BodyExcerpt string
+	Log         []RetryAttempt
+	Err         error
+}
+
+func (e *RetryExhaustedError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(ErrMaxRetriesExceeded.Error())
+	builder.WriteString(fmt.Sprintf(": %d attempts", e.Attempts))
+
+	if e.LastStatus != 0 {
+		builder.WriteString(fmt.Sprintf(", last status %d", e.LastStatus))
+	}
+	if e.Err != nil {
+		builder.WriteString(": ")
+		builder.WriteString(e.Err.Error())
+	}
+	if e.BodyExcerpt != "" {
+		builder.WriteString(": ")
+		builder.WriteString(e.BodyExcerpt)
+	}
+
+	return builder.String()
+}
+
+func (e *RetryExhaustedError) Is(target error) bool {
+	return target == ErrMaxRetriesExceeded
+}
+
+func (e *RetryExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+func bodyExcerpt(body []byte) string {
+	if len(body) > maxBodyExcerpt {
+		return string(body[:maxBodyExcerpt]) + "..."
+	}
+
+	return string(body)
+}
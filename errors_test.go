@@ -0,0 +1,110 @@
+package reqx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNonSuccessStatusReturnsHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"no such user"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	defer client.Close()
+
+	var apiError struct{ Message string }
+	resp, err := client.Get("/users/1").Do(nil, &apiError)
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusNotFound {
+		t.Fatalf("err = %v, want *HTTPError with status 404", err)
+	}
+	if errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Error("a non-retryable status was reported as exhausted retries")
+	}
+	if resp == nil || resp.Status != http.StatusNotFound {
+		t.Fatalf("resp = %+v, want the 404 response", resp)
+	}
+	if apiError.Message != "no such user" {
+		t.Errorf("error target = %+v, want it decoded", apiError)
+	}
+
+	_, _, err = Do[struct{}, struct{ Message string }](client.Get("/users/1"))
+	var typed *APIError[struct{ Message string }]
+	if !errors.As(err, &typed) || typed.Payload == nil || typed.Payload.Message != "no such user" {
+		t.Errorf("Do err = %v, want *APIError with the decoded payload", err)
+	}
+}
+
+func TestNonSuccessStreamReturnsHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("denied"))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	defer client.Close()
+
+	resp, err := client.Get("/download").DoStream()
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || string(httpErr.Body) != "denied" {
+		t.Fatalf("err = %v, want *HTTPError carrying the body excerpt", err)
+	}
+	body, _ := io.ReadAll(resp.BodyReader)
+	resp.BodyReader.Close()
+	if string(body) != "denied" {
+		t.Errorf("BodyReader = %q, want the excerpt", body)
+	}
+}
+
+func TestNotModifiedIsNotAnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	defer client.Close()
+
+	resp, err := client.Get("/resource").Header("If-None-Match", `"v1"`).DoRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.IsNotModified() {
+		t.Errorf("status = %d, want 304", resp.Status)
+	}
+}
+
+func TestTypedDoDecodesErrorPayloadAfterRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"message":"try later"}`))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).RetryConfig(2, 1).Build()
+	defer client.Close()
+
+	_, _, err := Do[struct{}, struct{ Message string }](client.Get("/jobs"))
+
+	var apiErr *APIError[struct{ Message string }]
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v (%T), want *APIError", err, err)
+	}
+	if apiErr.Status != http.StatusInternalServerError || apiErr.Payload == nil || apiErr.Payload.Message != "try later" {
+		t.Errorf("APIError = %+v, want status 500 with the decoded payload", apiErr)
+	}
+	if !errors.Is(err, ErrMaxRetriesExceeded) {
+		t.Error("the retry exhaustion was lost from the error chain")
+	}
+}
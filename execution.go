@@ -0,0 +1,31 @@
+package reqx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+type execution struct {
+	ctx            context.Context
+	stream         bool
+	shadow         bool
+	attempt        int
+	idempotencyKey string
+	requestIDs     []string
+	refreshed      bool
+	reuseRetried   bool
+	freshConn      bool
+	admitted       int
+	trial          string
+}
+
+func (c *Client) applyAttemptHeaders(exec *execution, req *http.Request) {
+	if c.attemptHeader != "" {
+		req.Header.Set(c.attemptHeader, strconv.Itoa(exec.attempt))
+	}
+
+	if c.idempotencyHeader != "" && req.Header.Get(c.idempotencyHeader) == "" {
+		req.Header.Set(c.idempotencyHeader, exec.idempotencyKey)
+	}
+}
@@ -0,0 +1,175 @@
+package reqx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Failures are collected rather than returned one by one, so a single error
+// describes everything that was off.
+type Expectation struct {
+	resp     *Response
+	failures []string
+}
+
+type ExpectationError struct {
+	Status   int
+	Body     []byte
+	Failures []string
+}
+
+func (e *ExpectationError) Error() string {
+	return fmt.Sprintf("reqx.expectation_failed: status %d: %s", e.Status, strings.Join(e.Failures, "; "))
+}
+
+func (e *ExpectationError) Is(target error) bool {
+	return target == ErrExpectationFailed
+}
+
+func (r *Response) Expect() *Expectation {
+	return &Expectation{resp: r}
+}
+
+func (e *Expectation) fail(format string, args ...any) *Expectation {
+	e.failures = append(e.failures, fmt.Sprintf(format, args...))
+	return e
+}
+
+func (e *Expectation) missing() bool {
+	if e.resp != nil {
+		return false
+	}
+	if len(e.failures) == 0 {
+		e.fail("no response")
+	}
+	return true
+}
+
+func (e *Expectation) Status(codes ...int) *Expectation {
+	if e.missing() || slices.Contains(codes, e.resp.Status) {
+		return e
+	}
+	return e.fail("status %d, want %v", e.resp.Status, codes)
+}
+
+func (e *Expectation) Success() *Expectation {
+	if e.missing() || e.resp.IsSuccess() {
+		return e
+	}
+	return e.fail("status %d, want success", e.resp.Status)
+}
+
+func (e *Expectation) Header(name, value string) *Expectation {
+	if e.missing() {
+		return e
+	}
+	if got := e.resp.Headers.Get(name); got != value {
+		return e.fail("header %s is %q, want %q", name, got, value)
+	}
+	return e
+}
+
+func (e *Expectation) HeaderMatches(name, pattern string) *Expectation {
+	if e.missing() {
+		return e
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return e.fail("header %s: invalid pattern %q: %v", name, pattern, err)
+	}
+	if got := e.resp.Headers.Get(name); !re.MatchString(got) {
+		return e.fail("header %s is %q, want match for %q", name, got, pattern)
+	}
+	return e
+}
+
+func (e *Expectation) BodyContains(substr string) *Expectation {
+	if e.missing() || bytes.Contains(e.resp.Body, []byte(substr)) {
+		return e
+	}
+	return e.fail("body does not contain %q", substr)
+}
+
+// Values are compared as JSON, so numbers match regardless of their Go type.
+func (e *Expectation) JSONField(path string, want any) *Expectation {
+	if e.missing() {
+		return e
+	}
+
+	value := e.resp.JSONPath(path)
+	if !value.Exists() {
+		return e.jsonFieldMissing(path, value)
+	}
+
+	var got any
+	if err := json.Unmarshal(value.Raw(), &got); err != nil {
+		return e.fail("json %s: %v", path, err)
+	}
+	encoded, err := json.Marshal(want)
+	if err != nil {
+		return e.fail("json %s: %v", path, err)
+	}
+	var expected any
+	if err := json.Unmarshal(encoded, &expected); err != nil {
+		return e.fail("json %s: %v", path, err)
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		return e.fail("json %s is %s, want %s", path, value.Raw(), encoded)
+	}
+	return e
+}
+
+func (e *Expectation) JSONFieldExists(path string) *Expectation {
+	if e.missing() {
+		return e
+	}
+	if value := e.resp.JSONPath(path); !value.Exists() {
+		return e.jsonFieldMissing(path, value)
+	}
+	return e
+}
+
+func (e *Expectation) jsonFieldMissing(path string, value JSONValue) *Expectation {
+	if value.Err() != nil {
+		return e.fail("json %s: %v", path, value.Err())
+	}
+	return e.fail("json %s is missing", path)
+}
+
+func (e *Expectation) MaxDuration(limit time.Duration) *Expectation {
+	if e.missing() || e.resp.Timings.Total <= limit {
+		return e
+	}
+	return e.fail("took %s, want at most %s", e.resp.Timings.Total, limit)
+}
+
+func (e *Expectation) Satisfies(check func(resp *Response) error) *Expectation {
+	if e.missing() {
+		return e
+	}
+	if err := check(e.resp); err != nil {
+		return e.fail("%v", err)
+	}
+	return e
+}
+
+func (e *Expectation) Err() error {
+	if len(e.failures) == 0 {
+		return nil
+	}
+
+	err := &ExpectationError{Failures: slices.Clone(e.failures)}
+	if e.resp != nil {
+		err.Status = e.resp.Status
+		err.Body = e.resp.Body
+	}
+	return err
+}
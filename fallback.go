@@ -0,0 +1,36 @@
+package reqx
+
+import (
+	"errors"
+)
+
+type Fallback func(req *RequestBuilder, lastResp *Response, lastErr error) (*Response, error)
+
+func (h *ClientBuilder) Fallback(fallback Fallback) *ClientBuilder {
+	h.fallback = fallback
+	return h
+}
+
+func (c *RequestBuilder) Fallback(fallback Fallback) *RequestBuilder {
+	c.fallback = fallback
+	return c
+}
+
+// The fallback only runs once retries are exhausted or the circuit breaker
+// rejected the request; aborted and canceled requests keep their error.
+func (c *RequestBuilder) applyFallback(response *Response, err error) (*Response, error) {
+	fallback := c.fallback
+	if fallback == nil {
+		fallback = c.client.fallback
+	}
+	if fallback == nil || !(errors.Is(err, ErrMaxRetriesExceeded) || errors.Is(err, ErrCircuitOpen)) {
+		return response, err
+	}
+
+	fallbackResp, fallbackErr := fallback(c, response, err)
+	if response != nil && response != fallbackResp && response.BodyReader != nil {
+		response.BodyReader.Close()
+	}
+
+	return fallbackResp, fallbackErr
+}
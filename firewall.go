@@ -0,0 +1,191 @@
+package reqx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+type FirewallPolicy struct {
+	AllowSchemes []string
+	AllowHosts   []string
+	DenyHosts    []string
+	AllowPorts   []int
+	DenyCIDRs    []netip.Prefix
+	BlockPrivate bool
+	Check        func(target *url.URL) error
+}
+
+type PolicyViolationError struct {
+	URL    string
+	Reason string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("reqx.policy_violation: %s: %s", e.URL, e.Reason)
+}
+
+func (e *PolicyViolationError) Is(target error) bool {
+	return target == ErrDeniedByPolicy
+}
+
+const maxRedirects = 10
+
+type firewall struct {
+	policy   FirewallPolicy
+	resolver *net.Resolver
+}
+
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+}
+
+func (h *ClientBuilder) Firewall(policy FirewallPolicy) *ClientBuilder {
+	fw := &firewall{policy: policy, resolver: net.DefaultResolver}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   fw.control,
+	}
+	h.transport.DialContext = dialer.DialContext
+	h.firewall = fw
+
+	return h
+}
+
+func (f *firewall) check(ctx context.Context, target *url.URL) error {
+	deny := func(format string, args ...any) error {
+		return &PolicyViolationError{URL: target.Redacted(), Reason: fmt.Sprintf(format, args...)}
+	}
+
+	scheme := strings.ToLower(target.Scheme)
+	if len(f.policy.AllowSchemes) > 0 && !slices.Contains(f.policy.AllowSchemes, scheme) {
+		return deny("scheme %q is not allowed", scheme)
+	}
+
+	host := strings.ToLower(target.Hostname())
+	if slices.ContainsFunc(f.policy.DenyHosts, func(pattern string) bool { return matchHost(pattern, host) }) {
+		return deny("host %q is denied", host)
+	}
+	if len(f.policy.AllowHosts) > 0 && !slices.ContainsFunc(f.policy.AllowHosts, func(pattern string) bool { return matchHost(pattern, host) }) {
+		return deny("host %q is not allowed", host)
+	}
+
+	port := targetPort(target)
+	if len(f.policy.AllowPorts) > 0 && !slices.Contains(f.policy.AllowPorts, port) {
+		return deny("port %d is not allowed", port)
+	}
+
+	if f.policy.BlockPrivate || len(f.policy.DenyCIDRs) > 0 {
+		addrs, err := f.resolve(ctx, host)
+		if err != nil {
+			return err
+		}
+		for _, addr := range addrs {
+			addr = addr.Unmap()
+			if reason := f.deniedAddr(addr); reason != "" {
+				return deny("address %s is %s", addr, reason)
+			}
+		}
+	}
+
+	if f.policy.Check != nil {
+		if err := f.policy.Check(target); err != nil {
+			return &PolicyViolationError{URL: target.Redacted(), Reason: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+func (f *firewall) control(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return &PolicyViolationError{URL: address, Reason: "unparseable dial address"}
+	}
+
+	addr := addrPort.Addr().Unmap()
+	if reason := f.deniedAddr(addr); reason != "" {
+		return &PolicyViolationError{URL: address, Reason: fmt.Sprintf("address %s is %s", addr, reason)}
+	}
+
+	return nil
+}
+
+func (f *firewall) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	return f.check(req.Context(), req.URL)
+}
+
+func (f *firewall) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+
+	return f.resolver.LookupNetIP(ctx, "ip", host)
+}
+
+func (f *firewall) deniedAddr(addr netip.Addr) string {
+	for _, prefix := range f.policy.DenyCIDRs {
+		if prefix.Contains(addr) {
+			return "in denied range " + prefix.String()
+		}
+	}
+
+	if f.policy.BlockPrivate && !isPublicAddr(addr) {
+		return "not a public address"
+	}
+
+	return ""
+}
+
+func isPublicAddr(addr netip.Addr) bool {
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+
+	return !slices.ContainsFunc(nonPublicPrefixes, func(prefix netip.Prefix) bool {
+		return prefix.Contains(addr)
+	})
+}
+
+func matchHost(pattern string, host string) bool {
+	pattern = strings.ToLower(pattern)
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+
+	return pattern == host
+}
+
+func targetPort(target *url.URL) int {
+	if port, err := strconv.Atoi(target.Port()); err == nil {
+		return port
+	}
+
+	switch strings.ToLower(target.Scheme) {
+	case "https", "wss":
+		return 443
+	default:
+		return 80
+	}
+}
@@ -0,0 +1,97 @@
+package reqx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestFirewallRejectsPerRequestTransport(t *testing.T) {
+	client := NewClientBuilder().
+		BaseUrl("http://example.com").
+		Firewall(FirewallPolicy{AllowHosts: []string{"example.com"}}).
+		Build()
+	defer client.Close()
+
+	called := false
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		return nil, errors.New("unexpected round trip")
+	})
+
+	_, err := client.Get("/").Transport(transport).DoRaw()
+	if !errors.Is(err, ErrDeniedByPolicy) {
+		t.Fatalf("err = %v, want ErrDeniedByPolicy", err)
+	}
+	if called {
+		t.Error("per-request transport was used despite the firewall")
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFirewallChecksRedirectHops(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusFound)
+	}))
+	defer origin.Close()
+
+	originURL, _ := url.Parse(origin.URL)
+	port, _ := strconv.Atoi(originURL.Port())
+	client := NewClientBuilder().
+		BaseUrl(origin.URL).
+		Firewall(FirewallPolicy{AllowPorts: []int{port}}).
+		Build()
+	defer client.Close()
+
+	_, err := client.Get("/").DoRaw()
+	if !errors.Is(err, ErrDeniedByPolicy) {
+		t.Fatalf("err = %v, want ErrDeniedByPolicy for the redirect hop", err)
+	}
+}
+
+func TestFirewallBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a private address")
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		Firewall(FirewallPolicy{BlockPrivate: true}).
+		Build()
+	defer client.Close()
+
+	if _, err := client.Get("/").DoRaw(); !errors.Is(err, ErrDeniedByPolicy) {
+		t.Fatalf("err = %v, want ErrDeniedByPolicy", err)
+	}
+
+	// The dial-time check catches addresses that only show up after the
+	// pre-flight resolution, such as a rebound DNS answer.
+	for _, address := range []string{"127.0.0.1:80", "10.1.2.3:443", "[::1]:80", "169.254.169.254:80"} {
+		if err := client.firewall.control("tcp", address, nil); !errors.Is(err, ErrDeniedByPolicy) {
+			t.Errorf("dial %s: err = %v, want ErrDeniedByPolicy", address, err)
+		}
+	}
+	if err := client.firewall.control("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("dial public address: %v", err)
+	}
+}
+
+func TestFirewallDisablesEnvironmentProxy(t *testing.T) {
+	client := NewClientBuilder().Firewall(FirewallPolicy{BlockPrivate: true}).Build()
+	defer client.Close()
+
+	if client.client.Transport.(*http.Transport).Proxy != nil {
+		t.Error("firewalled transport still routes through a proxy")
+	}
+}
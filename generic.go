@@ -0,0 +1,60 @@
+package reqx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type APIError[E any] struct {
+	HTTPError
+	Payload *E
+
+	err error
+}
+
+func (e *APIError[E]) Error() string {
+	return fmt.Sprintf("reqx.api_error: status %d", e.Status)
+}
+
+// Unwrap returns the error the request failed with, so a
+// RetryExhaustedError around the HTTPError stays visible.
+func (e *APIError[E]) Unwrap() error {
+	if e.err != nil {
+		return e.err
+	}
+	return &e.HTTPError
+}
+
+func Do[S any, E any](rb *RequestBuilder) (*S, *Response, error) {
+	success := new(S)
+
+	resp, err := rb.Do(success, nil)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		apiErr := &APIError[E]{HTTPError: *httpErr, err: err}
+
+		payload := new(E)
+		if len(httpErr.Body) > 0 && json.Unmarshal(httpErr.Body, payload) == nil {
+			apiErr.Payload = payload
+		}
+
+		return nil, resp, apiErr
+	}
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return success, resp, nil
+}
+
+func Exec[S any](rb *RequestBuilder) (*S, *Response, error) {
+	success := new(S)
+
+	resp, err := rb.Do(success, nil)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return success, resp, nil
+}
@@ -0,0 +1,336 @@
+package reqx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+type harLog struct {
+	Log struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HAR has no encoding field for request bodies; binary ones are marked with
+// the custom _encoding field.
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"_encoding,omitempty"`
+}
+
+type harContent struct {
+	Size      int    `json:"size"`
+	MimeType  string `json:"mimeType"`
+	Text      string `json:"text,omitempty"`
+	Encoding  string `json:"encoding,omitempty"`
+	Truncated bool   `json:"_truncated,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type HARRecorder struct {
+	mu           sync.Mutex
+	entries      []harEntry
+	maxBodyBytes int64
+}
+
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{maxBodyBytes: defaultDumpBodyBytes}
+}
+
+// A negative limit leaves bodies out.
+func (r *HARRecorder) MaxBodyBytes(limit int64) *HARRecorder {
+	r.maxBodyBytes = limit
+	return r
+}
+
+func (h *ClientBuilder) RecordHAR(recorder *HARRecorder) *ClientBuilder {
+	h.har = recorder
+	return h
+}
+
+func (r *HARRecorder) add(entry harEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = append(r.entries, entry)
+}
+
+func (r *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	var archive harLog
+	archive.Log.Version = "1.2"
+	archive.Log.Creator = harCreator{Name: "reqx", Version: "1.0"}
+
+	r.mu.Lock()
+	archive.Log.Entries = slices.Clone(r.entries)
+	r.mu.Unlock()
+	if archive.Log.Entries == nil {
+		archive.Log.Entries = []harEntry{}
+	}
+	slices.SortStableFunc(archive.Log.Entries, func(a, b harEntry) int {
+		return a.StartedDateTime.Compare(b.StartedDateTime)
+	})
+
+	data, err := json.MarshalIndent(archive, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+func (r *HARRecorder) Save(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := r.WriteTo(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// recordHAR sits next to the traffic dump, below the HTTP cache, so entries
+// reflect what went over the wire with the same redaction applied.
+func (c *Client) recordHAR(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if isShadowRequest(req) {
+			return next(req)
+		}
+
+		limit := c.har.maxBodyBytes
+		entry := harEntry{
+			StartedDateTime: time.Now(),
+			Request:         c.harRequest(req, limit),
+		}
+
+		resp, err := next(req)
+		waited := time.Since(entry.StartedDateTime)
+		entry.Timings.Wait = milliseconds(waited)
+		if err != nil {
+			entry.Time = entry.Timings.Wait
+			entry.Response = harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+			entry.Error = c.redactError(err)
+			c.har.add(entry)
+			return resp, err
+		}
+
+		entry.Response = harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(c.redactHeaders(resp.Header)),
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+		}
+		resp.Body = &dumpBody{
+			ReadCloser: resp.Body,
+			limit:      limit,
+			done: func(body []byte, truncated bool) {
+				entry.Timings.Receive = milliseconds(time.Since(entry.StartedDateTime) - waited)
+				entry.Time = entry.Timings.Wait + entry.Timings.Receive
+				entry.Response.BodySize = len(body)
+				entry.Response.Content = harBody(body, resp.Header.Get("Content-Type"))
+				entry.Response.Content.Truncated = truncated
+				c.har.add(entry)
+			},
+		}
+		return resp, nil
+	}
+}
+
+func (c *Client) harRequest(req *http.Request, limit int64) harRequest {
+	recorded := harRequest{
+		Method:      req.Method,
+		URL:         c.redactURL(req.URL.String()),
+		HTTPVersion: req.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     harHeaders(c.redactHeaders(req.Header)),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+	}
+	if parsed, err := url.Parse(recorded.URL); err == nil {
+		for name, values := range parsed.Query() {
+			for _, value := range values {
+				recorded.QueryString = append(recorded.QueryString, harNameValue{Name: name, Value: value})
+			}
+		}
+		slices.SortStableFunc(recorded.QueryString, func(a, b harNameValue) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+	}
+
+	if req.GetBody != nil && limit >= 0 {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := readDebugBody(body, limit)
+			body.Close()
+			recorded.BodySize = len(data)
+			if len(data) > 0 {
+				content := harBody(data, req.Header.Get("Content-Type"))
+				recorded.PostData = &harPostData{MimeType: content.MimeType, Text: content.Text, Encoding: content.Encoding}
+			}
+		}
+	}
+	return recorded
+}
+
+func harHeaders(headers http.Header) []harNameValue {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, value := range headers[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+// Binary bodies are stored base64-encoded, as the HAR format allows.
+func harBody(body []byte, contentType string) harContent {
+	content := harContent{Size: len(body), MimeType: contentType}
+	if len(body) == 0 {
+		return content
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if utf8.Valid(body) && !strings.HasPrefix(mediaType, "image/") {
+		content.Text = string(body)
+		return content
+	}
+	content.Text = base64.StdEncoding.EncodeToString(body)
+	content.Encoding = "base64"
+	return content
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func HARInteractions(path string) ([]Interaction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var archive harLog
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, err
+	}
+
+	var interactions []Interaction
+	for _, entry := range archive.Log.Entries {
+		if entry.Response.Status == 0 {
+			continue
+		}
+
+		response, err := harContentBody(entry.Response.Content)
+		if err != nil {
+			return nil, err
+		}
+		interaction := Interaction{
+			Request: RecordedRequest{
+				Method:  entry.Request.Method,
+				URL:     entry.Request.URL,
+				Headers: harHeaderMap(entry.Request.Headers),
+			},
+			Response: RecordedResponse{
+				Status:  entry.Response.Status,
+				Headers: harHeaderMap(entry.Response.Headers),
+				Body:    response,
+			},
+			RecordedAt: entry.StartedDateTime,
+			Duration:   time.Duration(entry.Time * float64(time.Millisecond)),
+		}
+		if postData := entry.Request.PostData; postData != nil {
+			interaction.Request.Body, err = harContentBody(harContent{Text: postData.Text, Encoding: postData.Encoding})
+			if err != nil {
+				return nil, err
+			}
+		}
+		interactions = append(interactions, interaction)
+	}
+	return interactions, nil
+}
+
+func harContentBody(content harContent) ([]byte, error) {
+	if content.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(content.Text)
+	}
+	return []byte(content.Text), nil
+}
+
+func harHeaderMap(pairs []harNameValue) http.Header {
+	headers := make(http.Header, len(pairs))
+	for _, pair := range pairs {
+		headers.Add(pair.Name, pair.Value)
+	}
+	return headers
+}
@@ -0,0 +1,47 @@
+package reqx
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestHARRoundTripsBinaryBodies(t *testing.T) {
+	upload := []byte{0x89, 'P', 'N', 'G', 0xff, 0x00, 0xfe}
+	download := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(download)
+	}))
+	defer server.Close()
+
+	recorder := NewHARRecorder()
+	client := NewClientBuilder().BaseUrl(server.URL).RecordHAR(recorder).Build()
+	defer client.Close()
+
+	_, err := client.Put("/blobs/1").Header("Content-Type", "application/octet-stream").Body(upload).DoRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "traffic.har")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	interactions, err := HARInteractions(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(interactions) != 1 {
+		t.Fatalf("got %d interactions, want 1", len(interactions))
+	}
+	if got := interactions[0].Request.Body; !bytes.Equal(got, upload) {
+		t.Errorf("request body = %x, want %x", got, upload)
+	}
+	if got := interactions[0].Response.Body; !bytes.Equal(got, download) {
+		t.Errorf("response body = %x, want %x", got, download)
+	}
+}
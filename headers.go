@@ -0,0 +1,85 @@
+package reqx
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type HeaderError struct {
+	Name string
+	Err  error
+}
+
+func (e *HeaderError) Error() string {
+	return fmt.Sprintf("reqx.header_error: %q: %v", e.Name, e.Err)
+}
+
+func (e *HeaderError) Unwrap() error {
+	return e.Err
+}
+
+func (h *ClientBuilder) AllowHeaders(names ...string) *ClientBuilder {
+	if h.allowedHeaders == nil {
+		h.allowedHeaders = make(map[string]bool)
+	}
+	for _, name := range names {
+		h.allowedHeaders[http.CanonicalHeaderKey(name)] = true
+	}
+
+	return h
+}
+
+func (c *Client) setHeader(header http.Header, name string, value string) error {
+	if !validHeaderName(name) {
+		return &HeaderError{Name: name, Err: ErrInvalidHeaderName}
+	}
+
+	name = http.CanonicalHeaderKey(name)
+	if c.allowedHeaders != nil && !c.allowedHeaders[name] {
+		return &HeaderError{Name: name, Err: ErrHeaderNotAllowed}
+	}
+	if !validHeaderValue(value) {
+		return &HeaderError{Name: name, Err: ErrInvalidHeaderValue}
+	}
+
+	header.Set(name, value)
+	return nil
+}
+
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		if !isTokenChar(name[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isTokenChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+
+	switch c {
+	case '!', '#', '$', '%', '&', '\'', '*', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+
+	return false
+}
+
+func validHeaderValue(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < ' ' && c != '\t' || c == 0x7f {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,96 @@
+package reqx
+
+import (
+	"net/http"
+	"time"
+)
+
+type hooks struct {
+	onRequest  []func(req *http.Request)
+	onResponse []func(resp *Response)
+	onError    []func(req *http.Request, err error)
+	onRetry    []func(attempt int, backoff time.Duration)
+
+	onRetryEvent []func(event RetryEvent)
+}
+
+type RetryEvent struct {
+	Attempt int
+	Wait    time.Duration
+	Status  int
+	Err     error
+	Method  string
+	URL     string
+	Time    time.Time
+}
+
+func (h *ClientBuilder) OnRequest(fn func(req *http.Request)) *ClientBuilder {
+	h.hooks.onRequest = append(h.hooks.onRequest, fn)
+	return h
+}
+
+func (h *ClientBuilder) OnResponse(fn func(resp *Response)) *ClientBuilder {
+	h.hooks.onResponse = append(h.hooks.onResponse, fn)
+	return h
+}
+
+func (h *ClientBuilder) OnError(fn func(req *http.Request, err error)) *ClientBuilder {
+	h.hooks.onError = append(h.hooks.onError, fn)
+	return h
+}
+
+func (h *ClientBuilder) OnRetry(fn func(attempt int, backoff time.Duration)) *ClientBuilder {
+	h.hooks.onRetry = append(h.hooks.onRetry, fn)
+	return h
+}
+
+func (h *ClientBuilder) OnRetryEvent(fn func(event RetryEvent)) *ClientBuilder {
+	h.hooks.onRetryEvent = append(h.hooks.onRetryEvent, fn)
+	return h
+}
+
+// Events are dropped rather than blocking the retry loop when the channel is
+// full.
+func (h *ClientBuilder) RetryEvents(events chan<- RetryEvent) *ClientBuilder {
+	return h.OnRetryEvent(func(event RetryEvent) {
+		select {
+		case events <- event:
+		default:
+		}
+	})
+}
+
+func (h *hooks) request(req *http.Request) {
+	for _, fn := range h.onRequest {
+		fn(req)
+	}
+}
+
+func (h *hooks) response(resp *Response) {
+	for _, fn := range h.onResponse {
+		fn(resp)
+	}
+}
+
+func (h *hooks) error(req *http.Request, err error) {
+	for _, fn := range h.onError {
+		fn(req, err)
+	}
+}
+
+func (h *hooks) retry(attempt int, backoff time.Duration) {
+	for _, fn := range h.onRetry {
+		fn(attempt, backoff)
+	}
+}
+
+func (h *hooks) retryEvent(event func() RetryEvent) {
+	if len(h.onRetryEvent) == 0 {
+		return
+	}
+
+	e := event()
+	for _, fn := range h.onRetryEvent {
+		fn(e)
+	}
+}
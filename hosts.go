@@ -0,0 +1,49 @@
+package reqx
+
+import (
+	"context"
+	"net"
+	"strings"
+)
+
+func (h *ClientBuilder) StaticHost(host string, address string) *ClientBuilder {
+	if h.staticHosts == nil {
+		h.staticHosts = make(map[string]string)
+	}
+	h.staticHosts[strings.ToLower(host)] = address
+	return h
+}
+
+type dialFunc func(ctx context.Context, network string, address string) (net.Conn, error)
+
+func staticHostsDialer(hosts map[string]string, next dialFunc) dialFunc {
+	if next == nil {
+		next = (&net.Dialer{}).DialContext
+	}
+
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		return next(ctx, network, resolveStaticHost(hosts, address))
+	}
+}
+
+func resolveStaticHost(hosts map[string]string, address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	host = strings.ToLower(host)
+
+	target, ok := hosts[net.JoinHostPort(host, port)]
+	if !ok {
+		target, ok = hosts[host]
+	}
+	if !ok {
+		return address
+	}
+
+	if _, _, err := net.SplitHostPort(target); err == nil {
+		return target
+	}
+
+	return net.JoinHostPort(target, port)
+}
@@ -0,0 +1,507 @@
+package reqx
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHTTPCacheEntries = 1024
+	maxCachedBodyBytes      = 8 << 20
+	maxHeuristicFreshness   = 24 * time.Hour
+)
+
+// Statuses that RFC 7231 defines as cacheable by default.
+var heuristicStatuses = []int{
+	http.StatusOK,
+	http.StatusNonAuthoritativeInfo,
+	http.StatusNoContent,
+	http.StatusMultipleChoices,
+	http.StatusMovedPermanently,
+	http.StatusNotFound,
+	http.StatusMethodNotAllowed,
+	http.StatusGone,
+	http.StatusRequestURITooLong,
+	http.StatusNotImplemented,
+}
+
+type CachedResponse struct {
+	Status       int
+	Header       http.Header
+	Body         []byte
+	Vary         http.Header
+	RequestTime  time.Time
+	ResponseTime time.Time
+}
+
+type CacheStore interface {
+	Get(key string) (*CachedResponse, bool)
+	Set(key string, entry *CachedResponse)
+	Delete(key string)
+}
+
+type memoryCacheStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*CachedResponse
+}
+
+func NewMemoryCacheStore(maxEntries int) CacheStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultHTTPCacheEntries
+	}
+
+	return &memoryCacheStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*CachedResponse),
+	}
+}
+
+func (s *memoryCacheStore) Get(key string) (*CachedResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	return entry, ok
+}
+
+func (s *memoryCacheStore) Set(key string, entry *CachedResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[key]; !ok && len(s.entries) >= s.maxEntries {
+		for k := range s.entries {
+			delete(s.entries, k)
+			break
+		}
+	}
+	s.entries[key] = entry
+}
+
+func (s *memoryCacheStore) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+}
+
+type httpCache struct {
+	store CacheStore
+
+	// Background revalidations run under the client's context and are
+	// waited for when the client closes.
+	ctx     context.Context
+	running sync.WaitGroup
+
+	mu           sync.Mutex
+	revalidating map[string]bool
+}
+
+type cacheHitKey struct{}
+
+func trackCacheHit(req *http.Request) (*http.Request, *bool) {
+	hit := new(bool)
+	return req.WithContext(context.WithValue(req.Context(), cacheHitKey{}, hit)), hit
+}
+
+func markCacheHit(req *http.Request) {
+	if hit, ok := req.Context().Value(cacheHitKey{}).(*bool); ok {
+		*hit = true
+	}
+}
+
+func (h *ClientBuilder) HTTPCache(store CacheStore) *ClientBuilder {
+	if store == nil {
+		store = NewMemoryCacheStore(defaultHTTPCacheEntries)
+	}
+
+	h.httpCache = &httpCache{store: store, revalidating: make(map[string]bool)}
+	return h
+}
+
+func (c *httpCache) forClient(ctx context.Context) *httpCache {
+	return &httpCache{store: c.store, ctx: ctx, revalidating: make(map[string]bool)}
+}
+
+func (c *httpCache) wait() {
+	c.running.Wait()
+}
+
+// The cache is private to the client: entries are keyed by URL and by the
+// configured credentials, so identities never share responses while OAuth1
+// nonces and refreshed tokens still hit the same entry.
+func httpCacheKey(req *http.Request) string {
+	key := req.URL.String()
+	if identity := credentialIdentityFrom(req.Context()); identity != "" {
+		key += "\x00" + identity
+	}
+	return key
+}
+
+func (c *httpCache) roundTrip(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if credentialIdentityFrom(req.Context()) == unidentifiedCredentials {
+			return next(req)
+		}
+		if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+			resp, err := next(req)
+			if err == nil && req.Method != http.MethodHead && req.Method != http.MethodOptions && resp.StatusCode < 400 {
+				c.store.Delete(httpCacheKey(req))
+			}
+			return resp, err
+		}
+
+		key := httpCacheKey(req)
+		requestDirectives := parseCacheControl(req.Header)
+		_, noStore := requestDirectives["no-store"]
+		conditional := req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != ""
+
+		var entry *CachedResponse
+		if !noStore && !conditional {
+			if cached, ok := c.store.Get(key); ok && cached.matchesVary(req) {
+				entry = cached
+			}
+		}
+
+		now := time.Now()
+		if entry != nil && entry.fresh(requestDirectives, now) {
+			markCacheHit(req)
+			return entry.response(req), nil
+		}
+		if entry != nil && entry.usableStale("stale-while-revalidate", requestDirectives, now) {
+			c.revalidateInBackground(key, req, entry, next)
+			markCacheHit(req)
+			return entry.response(req), nil
+		}
+		if _, ok := requestDirectives["only-if-cached"]; ok {
+			return &http.Response{
+				Status:     strconv.Itoa(http.StatusGatewayTimeout) + " " + http.StatusText(http.StatusGatewayTimeout),
+				StatusCode: http.StatusGatewayTimeout,
+				Proto:      "HTTP/1.1",
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     make(http.Header),
+				Body:       http.NoBody,
+				Request:    req,
+			}, nil
+		}
+
+		outgoing := req
+		if entry != nil {
+			outgoing = entry.revalidate(req)
+		}
+
+		requestTime := time.Now()
+		resp, err := next(outgoing)
+		if entry != nil && (err != nil || isStaleIfErrorStatus(resp.StatusCode)) &&
+			entry.usableStale("stale-if-error", requestDirectives, time.Now()) {
+			if resp != nil {
+				resp.Body.Close()
+			}
+			markCacheHit(req)
+			return entry.response(req), nil
+		}
+		if err != nil {
+			return resp, err
+		}
+
+		return c.update(key, req, entry, resp, requestTime, noStore), nil
+	}
+}
+
+func (c *httpCache) update(key string, req *http.Request, entry *CachedResponse, resp *http.Response, requestTime time.Time, noStore bool) *http.Response {
+	if entry != nil && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		entry = entry.refresh(resp.Header, requestTime, time.Now())
+		c.store.Set(key, entry)
+		markCacheHit(req)
+		return entry.response(req)
+	}
+
+	if noStore || !storable(resp) {
+		if resp.StatusCode < 400 {
+			c.store.Delete(key)
+		}
+		return resp
+	}
+
+	resp.Body = &cacheBody{
+		ReadCloser: resp.Body,
+		done: func(body []byte) {
+			c.store.Set(key, &CachedResponse{
+				Status:       resp.StatusCode,
+				Header:       resp.Header.Clone(),
+				Body:         body,
+				Vary:         varyHeaders(req, resp.Header),
+				RequestTime:  requestTime,
+				ResponseTime: time.Now(),
+			})
+		},
+	}
+	return resp
+}
+
+// At most one background revalidation runs per entry; it outlives the
+// request that triggered it.
+func (c *httpCache) revalidateInBackground(key string, req *http.Request, entry *CachedResponse, next RoundTripFunc) {
+	c.mu.Lock()
+	if c.revalidating[key] || c.ctx.Err() != nil {
+		c.mu.Unlock()
+		return
+	}
+	c.revalidating[key] = true
+	c.mu.Unlock()
+
+	// The client's context keeps the caller's trace hooks and cache-hit flag
+	// out of the background request.
+	revalidation := entry.revalidate(req.Clone(c.ctx))
+	c.running.Add(1)
+	go func() {
+		defer c.running.Done()
+		defer func() {
+			c.mu.Lock()
+			delete(c.revalidating, key)
+			c.mu.Unlock()
+		}()
+
+		requestTime := time.Now()
+		resp, err := next(revalidation)
+		if err != nil {
+			return
+		}
+		if isStaleIfErrorStatus(resp.StatusCode) {
+			resp.Body.Close()
+			return
+		}
+
+		resp = c.update(key, revalidation, entry, resp, requestTime, false)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+}
+
+func isStaleIfErrorStatus(status int) bool {
+	return status == http.StatusInternalServerError || status == http.StatusBadGateway ||
+		status == http.StatusServiceUnavailable || status == http.StatusGatewayTimeout
+}
+
+func storable(resp *http.Response) bool {
+	directives := parseCacheControl(resp.Header)
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+	if slices.Contains(resp.Header.Values("Vary"), "*") {
+		return false
+	}
+	if resp.ContentLength > maxCachedBodyBytes {
+		return false
+	}
+
+	_, maxAge := directives["max-age"]
+	explicit := maxAge || resp.Header.Get("Expires") != ""
+	validators := resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+	return slices.Contains(heuristicStatuses, resp.StatusCode) && (explicit || validators)
+}
+
+func varyHeaders(req *http.Request, header http.Header) http.Header {
+	vary := make(http.Header)
+	for _, value := range header.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name != "" {
+				vary[name] = req.Header.Values(name)
+			}
+		}
+	}
+	return vary
+}
+
+func (e *CachedResponse) matchesVary(req *http.Request) bool {
+	for name, values := range e.Vary {
+		if !slices.Equal(values, req.Header.Values(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *CachedResponse) age(now time.Time) time.Duration {
+	initial := time.Duration(0)
+	if date, err := http.ParseTime(e.Header.Get("Date")); err == nil {
+		initial = max(e.ResponseTime.Sub(date), 0)
+	}
+	if seconds, err := strconv.ParseInt(e.Header.Get("Age"), 10, 64); err == nil {
+		initial = max(initial, time.Duration(seconds)*time.Second)
+	}
+
+	initial += e.ResponseTime.Sub(e.RequestTime)
+	return initial + now.Sub(e.ResponseTime)
+}
+
+func (e *CachedResponse) lifetime() time.Duration {
+	directives := parseCacheControl(e.Header)
+	if _, ok := directives["no-cache"]; ok {
+		return 0
+	}
+	if value, ok := directives["max-age"]; ok {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	date, err := http.ParseTime(e.Header.Get("Date"))
+	if err != nil {
+		date = e.ResponseTime
+	}
+	if expires := e.Header.Get("Expires"); expires != "" {
+		at, err := http.ParseTime(expires)
+		if err != nil {
+			return 0
+		}
+		return at.Sub(date)
+	}
+
+	if lastModified, err := http.ParseTime(e.Header.Get("Last-Modified")); err == nil && slices.Contains(heuristicStatuses, e.Status) {
+		return min(date.Sub(lastModified)/10, maxHeuristicFreshness)
+	}
+	return 0
+}
+
+func (e *CachedResponse) fresh(requestDirectives map[string]string, now time.Time) bool {
+	if _, ok := requestDirectives["no-cache"]; ok {
+		return false
+	}
+
+	lifetime := e.lifetime()
+	if value, ok := requestDirectives["max-age"]; ok {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		lifetime = min(lifetime, time.Duration(seconds)*time.Second)
+	}
+
+	return e.age(now) < lifetime
+}
+
+// RFC 5861 extensions let a stale entry be served for a while past its
+// lifetime; stale-if-error may also come from the request.
+func (e *CachedResponse) usableStale(directive string, requestDirectives map[string]string, now time.Time) bool {
+	directives := parseCacheControl(e.Header)
+	if _, ok := directives["must-revalidate"]; ok {
+		return false
+	}
+	if _, ok := requestDirectives["no-cache"]; ok && directive == "stale-while-revalidate" {
+		return false
+	}
+
+	value, ok := directives[directive]
+	if requested, found := requestDirectives[directive]; found && directive == "stale-if-error" {
+		value, ok = requested, true
+	}
+	if !ok {
+		return false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return false
+	}
+	return e.age(now) < e.lifetime()+time.Duration(seconds)*time.Second
+}
+
+func (e *CachedResponse) revalidate(req *http.Request) *http.Request {
+	etag, lastModified := e.Header.Get("ETag"), e.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return req
+	}
+
+	revalidation := req.Clone(req.Context())
+	if etag != "" {
+		revalidation.Header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		revalidation.Header.Set("If-Modified-Since", lastModified)
+	}
+	return revalidation
+}
+
+func (e *CachedResponse) refresh(header http.Header, requestTime, responseTime time.Time) *CachedResponse {
+	refreshed := *e
+	refreshed.Header = e.Header.Clone()
+	for name, values := range header {
+		if name == "Content-Length" {
+			continue
+		}
+		refreshed.Header[name] = values
+	}
+	refreshed.RequestTime = requestTime
+	refreshed.ResponseTime = responseTime
+	return &refreshed
+}
+
+func (e *CachedResponse) response(req *http.Request) *http.Response {
+	header := e.Header.Clone()
+	header.Set("Age", strconv.FormatInt(int64(e.age(time.Now())/time.Second), 10))
+
+	return &http.Response{
+		Status:        strconv.Itoa(e.Status) + " " + http.StatusText(e.Status),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, argument, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name != "" {
+				directives[strings.ToLower(name)] = strings.Trim(argument, `"`)
+			}
+		}
+	}
+	return directives
+}
+
+// Bodies are stored once they have been read to the end; responses that are
+// closed early or grow past the limit are not cached.
+type cacheBody struct {
+	io.ReadCloser
+	buf      bytes.Buffer
+	overflow bool
+	done     func(body []byte)
+}
+
+func (b *cacheBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && !b.overflow {
+		if b.buf.Len()+n > maxCachedBodyBytes {
+			b.overflow = true
+			b.buf = bytes.Buffer{}
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF && !b.overflow && b.done != nil {
+		b.done(bytes.Clone(b.buf.Bytes()))
+		b.done = nil
+	}
+	return n, err
+}
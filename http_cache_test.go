@@ -0,0 +1,94 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPCacheHitsForOAuth1Requests(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	store := NewMemoryCacheStore(10)
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		OAuth1("consumer", "secret", "token", "token-secret").
+		HTTPCache(store).
+		Build()
+	defer client.Close()
+
+	for range 3 {
+		if _, err := client.Get("/resource").DoRaw(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := hits.Load(); got != 1 {
+		t.Errorf("server hits = %d, want 1", got)
+	}
+
+	other := NewClientBuilder().
+		BaseUrl(server.URL).
+		OAuth1("consumer", "secret", "other-token", "token-secret").
+		HTTPCache(store).
+		Build()
+	defer other.Close()
+
+	if _, err := other.Get("/resource").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if got := hits.Load(); got != 2 {
+		t.Errorf("server hits = %d, want a miss for a different access token", got)
+	}
+}
+
+func TestHTTPCacheCloseStopsBackgroundRevalidation(t *testing.T) {
+	revalidating := make(chan struct{})
+	canceled := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Cache-Control", "max-age=0, stale-while-revalidate=60")
+			w.Write([]byte("v1"))
+			return
+		}
+		close(revalidating)
+		<-r.Context().Done()
+		close(canceled)
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).HTTPCache(nil).Build()
+
+	for range 2 {
+		if _, err := client.Get("/resource").DoRaw(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case <-revalidating:
+	case <-time.After(time.Second):
+		t.Fatal("background revalidation did not start")
+	}
+
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(client.httpCache.revalidating); n != 0 {
+		t.Errorf("%d revalidations still running after Close", n)
+	}
+
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("revalidation request was not canceled by Close")
+	}
+}
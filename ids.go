@@ -0,0 +1,85 @@
+package reqx
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type IDGenerator func() string
+
+func (h *ClientBuilder) IDGenerator(generator IDGenerator) *ClientBuilder {
+	h.idGenerator = generator
+	return h
+}
+
+func UUIDGenerator() IDGenerator {
+	return uuid.NewString
+}
+
+func SequentialIDs(prefix string) IDGenerator {
+	var counter atomic.Uint64
+	return func() string {
+		return prefix + strconv.FormatUint(counter.Add(1), 10)
+	}
+}
+
+// Within one millisecond the random part is incremented, so IDs from one
+// generator stay sortable.
+func ULIDGenerator() IDGenerator {
+	var mu sync.Mutex
+	var lastMs uint64
+	var entropy [10]byte
+
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		ms := uint64(time.Now().UnixMilli())
+		if ms == lastMs {
+			for i := len(entropy) - 1; i >= 0; i-- {
+				entropy[i]++
+				if entropy[i] != 0 {
+					break
+				}
+			}
+		} else {
+			lastMs = ms
+			_, _ = rand.Read(entropy[:])
+		}
+
+		var id [16]byte
+		binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+		binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+		copy(id[6:], entropy[:])
+
+		return encodeULID(id)
+	}
+}
+
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[0:8])
+	lo := binary.BigEndian.Uint64(id[8:16])
+
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockfordBase32[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+func (c *Client) newID() string {
+	if c.idGenerator == nil {
+		return uuid.NewString()
+	}
+	return c.idGenerator()
+}
@@ -0,0 +1,383 @@
+package reqx
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+type JWSAlgorithm string
+
+const (
+	JWSHS256 JWSAlgorithm = "HS256"
+	JWSRS256 JWSAlgorithm = "RS256"
+	JWSES256 JWSAlgorithm = "ES256"
+)
+
+type JOSEConfig struct {
+	SignAlgorithm JWSAlgorithm
+	SigningKey    any
+	VerifyKey     any
+	EncryptKey    any
+	DecryptKey    any
+	KeyID         string
+
+	AllowUnprotectedResponses bool
+}
+
+var b64 = base64.RawURLEncoding
+
+func SignJWS(payload []byte, alg JWSAlgorithm, key any, headers map[string]any) (string, error) {
+	header := map[string]any{"alg": string(alg)}
+	for k, v := range headers {
+		header[k] = v
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := b64.EncodeToString(headerJSON) + "." + b64.EncodeToString(payload)
+	signature, err := jwsSign(alg, key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + b64.EncodeToString(signature), nil
+}
+
+func VerifyJWS(token string, key any) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: expected 3 JWS segments, got %d", ErrInvalidJOSE, len(parts))
+	}
+
+	header, err := decodeJOSEHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := b64.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+	}
+
+	alg, _ := header["alg"].(string)
+	if err := jwsVerify(JWSAlgorithm(alg), key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	payload, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+	}
+
+	return payload, nil
+}
+
+func EncryptJWE(plaintext []byte, key any, headers map[string]any) (string, error) {
+	header := map[string]any{"enc": "A256GCM"}
+	for k, v := range headers {
+		header[k] = v
+	}
+
+	var cek, encryptedKey []byte
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		header["alg"] = "RSA-OAEP-256"
+		cek = make([]byte, 32)
+		if _, err := rand.Read(cek); err != nil {
+			return "", err
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, k, cek, nil)
+		if err != nil {
+			return "", err
+		}
+		encryptedKey = wrapped
+	case []byte:
+		if len(k) != 32 {
+			return "", fmt.Errorf("%w: direct encryption requires a 256-bit key", ErrInvalidJOSE)
+		}
+		header["alg"] = "dir"
+		cek = k
+	default:
+		return "", fmt.Errorf("%w: unsupported encryption key %T", ErrInvalidJOSE, key)
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	protected := b64.EncodeToString(headerJSON)
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nil, iv, plaintext, []byte(protected))
+	ciphertext, tag := sealed[:len(sealed)-gcm.Overhead()], sealed[len(sealed)-gcm.Overhead():]
+
+	return strings.Join([]string{
+		protected,
+		b64.EncodeToString(encryptedKey),
+		b64.EncodeToString(iv),
+		b64.EncodeToString(ciphertext),
+		b64.EncodeToString(tag),
+	}, "."), nil
+}
+
+func DecryptJWE(token string, key any) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 JWE segments, got %d", ErrInvalidJOSE, len(parts))
+	}
+
+	header, err := decodeJOSEHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if enc, _ := header["enc"].(string); enc != "A256GCM" {
+		return nil, fmt.Errorf("%w: unsupported enc %q", ErrInvalidJOSE, enc)
+	}
+
+	segments := make([][]byte, 4)
+	for i, part := range parts[1:] {
+		segments[i], err = b64.DecodeString(part)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+		}
+	}
+	encryptedKey, iv, ciphertext, tag := segments[0], segments[1], segments[2], segments[3]
+
+	var cek []byte
+	alg, _ := header["alg"].(string)
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if alg != "RSA-OAEP-256" {
+			return nil, fmt.Errorf("%w: unexpected alg %q", ErrInvalidJOSE, alg)
+		}
+		cek, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, k, encryptedKey, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+		}
+	case []byte:
+		if alg != "dir" {
+			return nil, fmt.Errorf("%w: unexpected alg %q", ErrInvalidJOSE, alg)
+		}
+		cek = k
+	default:
+		return nil, fmt.Errorf("%w: unsupported decryption key %T", ErrInvalidJOSE, key)
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: invalid IV length", ErrInvalidJOSE)
+	}
+
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+	}
+
+	return plaintext, nil
+}
+
+func NewJOSETransformer(config JOSEConfig) BodyTransformer {
+	return &joseTransformer{config: config}
+}
+
+type joseTransformer struct {
+	config JOSEConfig
+}
+
+func (t *joseTransformer) TransformRequest(body []byte, headers http.Header) ([]byte, error) {
+	extra := map[string]any{}
+	if t.config.KeyID != "" {
+		extra["kid"] = t.config.KeyID
+	}
+	if cty := headers.Get("Content-Type"); cty != "" {
+		extra["cty"] = cty
+	}
+
+	if t.config.SigningKey != nil {
+		token, err := SignJWS(body, t.config.SignAlgorithm, t.config.SigningKey, extra)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(token)
+		extra["cty"] = "JWT"
+	}
+
+	if t.config.EncryptKey != nil {
+		token, err := EncryptJWE(body, t.config.EncryptKey, extra)
+		if err != nil {
+			return nil, err
+		}
+		body = []byte(token)
+	}
+
+	if t.config.SigningKey != nil || t.config.EncryptKey != nil {
+		headers.Set("Content-Type", "application/jose")
+	}
+
+	return body, nil
+}
+
+// With a VerifyKey or DecryptKey, responses that are not signed or
+// encrypted are rejected, so a man in the middle cannot downgrade them to
+// plain JSON. AllowUnprotectedResponses lets bodies that are not JOSE
+// objects at all pass through for APIs that protect only some endpoints.
+func (t *joseTransformer) TransformResponse(body []byte, headers http.Header) ([]byte, error) {
+	if t.config.DecryptKey == nil && t.config.VerifyKey == nil {
+		return body, nil
+	}
+
+	token := string(bytes.TrimSpace(body))
+	segments := strings.Count(token, ".") + 1
+	if t.config.AllowUnprotectedResponses && (strings.ContainsAny(token, " {[\"\n") || (segments != 3 && segments != 5)) {
+		return body, nil
+	}
+
+	if t.config.DecryptKey != nil {
+		if segments != 5 {
+			return nil, fmt.Errorf("%w: response is not encrypted", ErrInvalidJOSE)
+		}
+		plaintext, err := DecryptJWE(token, t.config.DecryptKey)
+		if err != nil {
+			return nil, err
+		}
+		token = string(plaintext)
+		segments = strings.Count(token, ".") + 1
+		body = plaintext
+	}
+
+	if t.config.VerifyKey != nil {
+		if segments != 3 {
+			return nil, fmt.Errorf("%w: response is not signed", ErrInvalidJOSE)
+		}
+		return VerifyJWS(token, t.config.VerifyKey)
+	}
+
+	return body, nil
+}
+
+func jwsSign(alg JWSAlgorithm, key any, input []byte) ([]byte, error) {
+	digest := sha256.Sum256(input)
+
+	switch alg {
+	case JWSHS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("%w: HS256 requires a []byte key", ErrInvalidJOSE)
+		}
+		mac := hmac.New(sha256.New, secret)
+		mac.Write(input)
+		return mac.Sum(nil), nil
+	case JWSRS256:
+		privateKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: RS256 requires an *rsa.PrivateKey", ErrInvalidJOSE)
+		}
+		return rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, digest[:])
+	case JWSES256:
+		privateKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok || privateKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: ES256 requires a P-256 *ecdsa.PrivateKey", ErrInvalidJOSE)
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+		return signature, nil
+	}
+
+	return nil, fmt.Errorf("%w: unsupported alg %q", ErrInvalidJOSE, alg)
+}
+
+func jwsVerify(alg JWSAlgorithm, key any, input []byte, signature []byte) error {
+	digest := sha256.Sum256(input)
+
+	switch alg {
+	case JWSHS256:
+		expected, err := jwsSign(alg, key, input)
+		if err != nil {
+			return err
+		}
+		if hmac.Equal(expected, signature) {
+			return nil
+		}
+	case JWSRS256:
+		publicKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("%w: RS256 requires an *rsa.PublicKey", ErrInvalidJOSE)
+		}
+		if rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) == nil {
+			return nil
+		}
+	case JWSES256:
+		publicKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || publicKey.Curve != elliptic.P256() {
+			return fmt.Errorf("%w: ES256 requires a P-256 *ecdsa.PublicKey", ErrInvalidJOSE)
+		}
+		if len(signature) == 64 {
+			r := new(big.Int).SetBytes(signature[:32])
+			s := new(big.Int).SetBytes(signature[32:])
+			if ecdsa.Verify(publicKey, digest[:], r, s) {
+				return nil
+			}
+		}
+	default:
+		return fmt.Errorf("%w: unsupported alg %q", ErrInvalidJOSE, alg)
+	}
+
+	return fmt.Errorf("%w: signature verification failed", ErrInvalidJOSE)
+}
+
+func decodeJOSEHeader(segment string) (map[string]any, error) {
+	headerJSON, err := b64.DecodeString(segment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+	}
+
+	var header map[string]any
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJOSE, err)
+	}
+
+	return header, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,134 @@
+package reqx
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestJOSEResponsesFailClosed(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := SignJWS([]byte(`{"ok":true}`), JWSES256, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transformer := NewJOSETransformer(JOSEConfig{VerifyKey: &key.PublicKey})
+
+	payload, err := transformer.TransformResponse([]byte(signed), http.Header{})
+	if err != nil || string(payload) != `{"ok":true}` {
+		t.Fatalf("verified payload = %q, %v", payload, err)
+	}
+
+	for name, body := range map[string]string{
+		"plain json":     `{"ok":true}`,
+		"dotted text":    "a.b.c",
+		"tampered token": signed[:len(signed)-4] + "AAAA",
+	} {
+		if _, err := transformer.TransformResponse([]byte(body), http.Header{}); !errors.Is(err, ErrInvalidJOSE) {
+			t.Errorf("%s: err = %v, want ErrInvalidJOSE", name, err)
+		}
+	}
+}
+
+func TestJOSEEncryptedResponsesFailClosed(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transformer := NewJOSETransformer(JOSEConfig{DecryptKey: key})
+
+	encrypted, err := EncryptJWE([]byte(`{"ok":true}`), &key.PublicKey, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload, err := transformer.TransformResponse([]byte(encrypted), http.Header{}); err != nil || string(payload) != `{"ok":true}` {
+		t.Fatalf("decrypted payload = %q, %v", payload, err)
+	}
+
+	if _, err := transformer.TransformResponse([]byte(`{"ok":true}`), http.Header{}); !errors.Is(err, ErrInvalidJOSE) {
+		t.Fatalf("err = %v, want ErrInvalidJOSE", err)
+	}
+}
+
+func TestJOSEAllowUnprotectedResponses(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transformer := NewJOSETransformer(JOSEConfig{VerifyKey: &key.PublicKey, AllowUnprotectedResponses: true})
+
+	if payload, err := transformer.TransformResponse([]byte(`{"ok":true}`), http.Header{}); err != nil || string(payload) != `{"ok":true}` {
+		t.Fatalf("payload = %q, %v", payload, err)
+	}
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	forged, _ := SignJWS([]byte(`{"ok":false}`), JWSES256, other, nil)
+	if _, err := transformer.TransformResponse([]byte(forged), http.Header{}); !errors.Is(err, ErrInvalidJOSE) {
+		t.Fatalf("forged token: err = %v, want ErrInvalidJOSE", err)
+	}
+}
+
+func TestES256RejectsOtherCurves(t *testing.T) {
+	for _, curve := range []elliptic.Curve{elliptic.P384(), elliptic.P521()} {
+		key, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := SignJWS([]byte("{}"), JWSES256, key, nil); !errors.Is(err, ErrInvalidJOSE) {
+			t.Errorf("%s sign: err = %v, want ErrInvalidJOSE", curve.Params().Name, err)
+		}
+
+		p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		token, _ := SignJWS([]byte("{}"), JWSES256, p256, nil)
+		if _, err := VerifyJWS(token, &key.PublicKey); !errors.Is(err, ErrInvalidJOSE) {
+			t.Errorf("%s verify: err = %v, want ErrInvalidJOSE", curve.Params().Name, err)
+		}
+	}
+}
+
+func TestJOSEUnprotectedErrorResponseKeepsStatus(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("<html>upstream unavailable</html>"))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		RetryConfig(1, 1).
+		BodyTransformer(NewJOSETransformer(JOSEConfig{VerifyKey: &key.PublicKey})).
+		Build()
+	defer client.Close()
+
+	resp, err := client.Get("/orders").DoRaw()
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Status != http.StatusServiceUnavailable {
+		t.Fatalf("err = %v, want an HTTPError with status 503", err)
+	}
+	if resp == nil || string(resp.Body) != "<html>upstream unavailable</html>" {
+		t.Errorf("resp = %+v, want the raw error page", resp)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want the 503 retried", got)
+	}
+}
@@ -0,0 +1,124 @@
+package reqx
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+type JSONValue struct {
+	raw    json.RawMessage
+	exists bool
+	err    error
+}
+
+func (r *Response) JSONPath(path string) JSONValue {
+	return jsonValueAt(r.Body, path)
+}
+
+func (r *Response) JSONMap() (map[string]any, error) {
+	var object map[string]any
+	if err := json.Unmarshal(r.Body, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+func jsonValueAt(body []byte, path string) JSONValue {
+	raw, ok, err := lookupJSONPath(body, path)
+	if err != nil || !ok {
+		return JSONValue{err: err}
+	}
+	return JSONValue{raw: bytes.TrimSpace(raw), exists: true}
+}
+
+func (v JSONValue) Get(path string) JSONValue {
+	if !v.exists {
+		return v
+	}
+	return jsonValueAt(v.raw, path)
+}
+
+func (v JSONValue) Exists() bool {
+	return v.exists
+}
+
+func (v JSONValue) IsNull() bool {
+	return v.exists && string(v.raw) == "null"
+}
+
+func (v JSONValue) Err() error {
+	return v.err
+}
+
+func (v JSONValue) Raw() json.RawMessage {
+	return v.raw
+}
+
+func (v JSONValue) String() string {
+	if !v.exists || v.IsNull() {
+		return ""
+	}
+
+	var s string
+	if json.Unmarshal(v.raw, &s) == nil {
+		return s
+	}
+	return string(v.raw)
+}
+
+// Numeric strings are accepted too, since many APIs quote large numbers.
+func (v JSONValue) Int() int64 {
+	text := v.String()
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return n
+	}
+	f, _ := strconv.ParseFloat(text, 64)
+	return int64(f)
+}
+
+func (v JSONValue) Float() float64 {
+	f, _ := strconv.ParseFloat(v.String(), 64)
+	return f
+}
+
+func (v JSONValue) Bool() bool {
+	b, _ := strconv.ParseBool(v.String())
+	return b
+}
+
+func (v JSONValue) Array() []JSONValue {
+	var items []json.RawMessage
+	if !v.exists || json.Unmarshal(v.raw, &items) != nil {
+		return nil
+	}
+
+	values := make([]JSONValue, len(items))
+	for i, item := range items {
+		values[i] = JSONValue{raw: bytes.TrimSpace(item), exists: true}
+	}
+	return values
+}
+
+func (v JSONValue) Map() map[string]JSONValue {
+	var fields map[string]json.RawMessage
+	if !v.exists || json.Unmarshal(v.raw, &fields) != nil {
+		return nil
+	}
+
+	values := make(map[string]JSONValue, len(fields))
+	for name, field := range fields {
+		values[name] = JSONValue{raw: bytes.TrimSpace(field), exists: true}
+	}
+	return values
+}
+
+func (v JSONValue) Decode(target any) error {
+	if v.err != nil {
+		return v.err
+	}
+	if !v.exists {
+		return ErrJSONPathNotFound
+	}
+	return json.Unmarshal(v.raw, target)
+}
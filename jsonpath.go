@@ -0,0 +1,90 @@
+package reqx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func lookupJSONPath(body []byte, path string) (json.RawMessage, bool, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := json.RawMessage(body)
+	for _, segment := range segments {
+		trimmed := bytes.TrimSpace(current)
+		if len(trimmed) == 0 {
+			return nil, false, nil
+		}
+
+		switch segment := segment.(type) {
+		case string:
+			if trimmed[0] != '{' {
+				return nil, false, nil
+			}
+			var object map[string]json.RawMessage
+			if err := json.Unmarshal(trimmed, &object); err != nil {
+				return nil, false, err
+			}
+			next, ok := object[segment]
+			if !ok {
+				return nil, false, nil
+			}
+			current = next
+		case int:
+			if trimmed[0] != '[' {
+				return nil, false, nil
+			}
+			var array []json.RawMessage
+			if err := json.Unmarshal(trimmed, &array); err != nil {
+				return nil, false, err
+			}
+			index := segment
+			if index < 0 {
+				index += len(array)
+			}
+			if index < 0 || index >= len(array) {
+				return nil, false, nil
+			}
+			current = array[index]
+		}
+	}
+
+	return current, true, nil
+}
+
+func parseJSONPath(path string) ([]any, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+
+	var segments []any
+	for path != "" {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated index in %q", ErrInvalidJSONPath, path)
+			}
+			index, err := strconv.Atoi(strings.TrimSpace(path[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid index in %q", ErrInvalidJSONPath, path)
+			}
+			segments = append(segments, index)
+			path = path[end+1:]
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			segments = append(segments, path[:end])
+			path = path[end:]
+		}
+	}
+
+	return segments, nil
+}
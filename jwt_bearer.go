@@ -0,0 +1,147 @@
+package reqx
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	jwtBearerGrantType     = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+	jwtBearerAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+)
+
+type JWTBearerConfig struct {
+	TokenURL   string
+	Issuer     string
+	Subject    string
+	Audience   string
+	Scopes     []string
+	Algorithm  JWSAlgorithm
+	PrivateKey any
+	KeyID      string
+	Lifetime   time.Duration
+
+	ClientAssertion bool
+	ExtraClaims     map[string]any
+}
+
+type JWTBearerTokenSource struct {
+	client *Client
+	config JWTBearerConfig
+
+	mu    sync.Mutex
+	token *OAuth2Token
+}
+
+func NewJWTBearerTokenSource(client *Client, config JWTBearerConfig) *JWTBearerTokenSource {
+	if client == nil {
+		client = NewClientBuilder().Build()
+	}
+	if config.Algorithm == "" {
+		config.Algorithm = JWSRS256
+	}
+	if config.Audience == "" {
+		config.Audience = config.TokenURL
+	}
+	if config.Lifetime <= 0 {
+		config.Lifetime = 5 * time.Minute
+	}
+
+	return &JWTBearerTokenSource{client: client, config: config}
+}
+
+func (h *ClientBuilder) JWTBearer(config JWTBearerConfig) *ClientBuilder {
+	return h.TokenSource(NewJWTBearerTokenSource(nil, config))
+}
+
+func (s *JWTBearerTokenSource) Token(ctx context.Context) (string, error) {
+	token, err := s.ExpiringToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return token.Value, nil
+}
+
+func (s *JWTBearerTokenSource) ExpiringToken(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.token.valid() {
+		if err := s.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Token{Value: s.token.AccessToken, Expiry: s.token.Expiry}, nil
+}
+
+func (s *JWTBearerTokenSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fetch(ctx)
+}
+
+func (s *JWTBearerTokenSource) fetch(ctx context.Context) error {
+	assertion, err := s.assertion()
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{}
+	if s.config.ClientAssertion {
+		form.Set("grant_type", "client_credentials")
+		form.Set("client_assertion_type", jwtBearerAssertionType)
+		form.Set("client_assertion", assertion)
+		if len(s.config.Scopes) > 0 {
+			form.Set("scope", strings.Join(s.config.Scopes, " "))
+		}
+	} else {
+		form.Set("grant_type", jwtBearerGrantType)
+		form.Set("assertion", assertion)
+	}
+
+	token, err := requestOAuth2Token(ctx, s.client, s.config.TokenURL, form)
+	if err != nil {
+		return err
+	}
+
+	s.token = token
+	return nil
+}
+
+func (s *JWTBearerTokenSource) assertion() (string, error) {
+	now := time.Now()
+
+	claims := map[string]any{}
+	for k, v := range s.config.ExtraClaims {
+		claims[k] = v
+	}
+	claims["iss"] = s.config.Issuer
+	claims["sub"] = cmp.Or(s.config.Subject, s.config.Issuer)
+	claims["aud"] = s.config.Audience
+	claims["iat"] = now.Unix()
+	claims["exp"] = now.Add(s.config.Lifetime).Unix()
+	claims["jti"] = s.client.newID()
+	if !s.config.ClientAssertion && len(s.config.Scopes) > 0 {
+		claims["scope"] = strings.Join(s.config.Scopes, " ")
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	headers := map[string]any{"typ": "JWT"}
+	if s.config.KeyID != "" {
+		headers["kid"] = s.config.KeyID
+	}
+
+	return SignJWS(payload, s.config.Algorithm, s.config.PrivateKey, headers)
+}
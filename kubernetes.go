@@ -0,0 +1,115 @@
+package reqx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const kubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+type KubernetesConfig struct {
+	ServiceAccountDir string
+	Host              string
+	Port              string
+	TokenRefresh      time.Duration
+}
+
+func NewKubernetesClientBuilder() (*ClientBuilder, error) {
+	return NewClientBuilder().KubernetesPreset(KubernetesConfig{})
+}
+
+func (h *ClientBuilder) KubernetesPreset(config KubernetesConfig) (*ClientBuilder, error) {
+	if config.ServiceAccountDir == "" {
+		config.ServiceAccountDir = kubernetesServiceAccountDir
+	}
+	if config.Host == "" {
+		config.Host = os.Getenv("KUBERNETES_SERVICE_HOST")
+	}
+	if config.Port == "" {
+		config.Port = os.Getenv("KUBERNETES_SERVICE_PORT")
+	}
+	if config.Host == "" || config.Port == "" {
+		return nil, ErrNotInCluster
+	}
+
+	ca, err := os.ReadFile(filepath.Join(config.ServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return nil, ErrInvalidCertificate
+	}
+
+	namespace, err := os.ReadFile(filepath.Join(config.ServiceAccountDir, "namespace"))
+	if err != nil {
+		return nil, err
+	}
+
+	source := FileTokenSource(filepath.Join(config.ServiceAccountDir, "token"), config.TokenRefresh)
+	if _, err := source.Token(context.Background()); err != nil {
+		return nil, err
+	}
+
+	h.transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+
+	return h.
+		BaseUrl("https://"+net.JoinHostPort(config.Host, config.Port)).
+		Variable("namespace", strings.TrimSpace(string(namespace))).
+		Header("Accept", "application/json").
+		TokenSource(source), nil
+}
+
+type fileTokenSource struct {
+	path    string
+	refresh time.Duration
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+func FileTokenSource(path string, refresh time.Duration) TokenSource {
+	if refresh <= 0 {
+		refresh = time.Minute
+	}
+
+	return &fileTokenSource{path: path, refresh: refresh}
+}
+
+func (s *fileTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	return s.read()
+}
+
+func (s *fileTokenSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.read()
+	return err
+}
+
+func (s *fileTokenSource) read() (string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+
+	s.token = strings.TrimSpace(string(data))
+	s.expires = time.Now().Add(s.refresh)
+	return s.token, nil
+}
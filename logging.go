@@ -0,0 +1,66 @@
+package reqx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logger routes the client's logs. Without one the client stays silent;
+// any logging library can be plugged in through a slog.Handler.
+func (h *ClientBuilder) Logger(logger *slog.Logger) *ClientBuilder {
+	h.logger = logger
+	return h
+}
+
+// LogLevel drops records below level, for the client logger as well as
+// loggers set on individual requests.
+func (h *ClientBuilder) LogLevel(level slog.Leveler) *ClientBuilder {
+	h.logLevel = level
+	return h
+}
+
+func (h *ClientBuilder) buildLogger() *slog.Logger {
+	if h.logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return withLogLevel(h.logger, h.logLevel)
+}
+
+type loggerKey struct{}
+
+// Token sources and other code handed a request's context log through the
+// request's logger.
+func withLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+func loggerFrom(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.New(slog.DiscardHandler)
+}
+
+func withLogLevel(logger *slog.Logger, level slog.Leveler) *slog.Logger {
+	if level == nil {
+		return logger
+	}
+	return slog.New(&levelHandler{Handler: logger.Handler(), level: level})
+}
+
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
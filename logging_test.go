@@ -0,0 +1,56 @@
+package reqx
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLoggerReceivesClientRecords(t *testing.T) {
+	server, _ := captureServer(t)
+	credentialsURL := strings.Replace(server.URL, "http://", "http://user:secret@", 1)
+
+	var logs bytes.Buffer
+	client := NewClientBuilder().
+		URLCredentials(URLCredentialsStrip).
+		Logger(slog.New(slog.NewJSONHandler(&logs, nil))).
+		Build()
+	defer client.Close()
+
+	if _, err := client.Get(credentialsURL + "/").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+
+	var record map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("log output %q: %v", logs.String(), err)
+	}
+	if record["package"] != "reqx" {
+		t.Errorf("package = %v, want reqx", record["package"])
+	}
+	if strings.Contains(logs.String(), "secret") {
+		t.Errorf("log leaked credentials: %s", logs.String())
+	}
+}
+
+func TestLogLevelFiltersClientRecords(t *testing.T) {
+	server, _ := captureServer(t)
+	credentialsURL := strings.Replace(server.URL, "http://", "http://user:secret@", 1)
+
+	var logs bytes.Buffer
+	client := NewClientBuilder().
+		URLCredentials(URLCredentialsStrip).
+		Logger(slog.New(slog.NewJSONHandler(&logs, nil))).
+		LogLevel(slog.LevelError).
+		Build()
+	defer client.Close()
+
+	if _, err := client.Get(credentialsURL + "/").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("warning passed an error-level filter: %s", logs.String())
+	}
+}
@@ -0,0 +1,51 @@
+package reqx
+
+import (
+	"net/http"
+	"time"
+)
+
+type RequestMetrics struct {
+	Method    string
+	Host      string
+	Route     string
+	Status    int
+	Attempt   int
+	Duration  time.Duration
+	FromCache bool
+	Err       error
+}
+
+type MetricsCollector interface {
+	ObserveRequest(metrics RequestMetrics)
+}
+
+type MetricsFunc func(metrics RequestMetrics)
+
+func (f MetricsFunc) ObserveRequest(metrics RequestMetrics) {
+	f(metrics)
+}
+
+func (h *ClientBuilder) Metrics(collector MetricsCollector) *ClientBuilder {
+	h.metrics = collector
+	return h
+}
+
+// Route is the request path before variable expansion and query parameters,
+// which keeps label cardinality low for templated paths.
+func (c *RequestBuilder) observeMetrics(exec *execution, req *http.Request, started time.Time, status int, fromCache *bool, err error) {
+	if c.client.metrics == nil || exec.shadow {
+		return
+	}
+
+	c.client.metrics.ObserveRequest(RequestMetrics{
+		Method:    req.Method,
+		Host:      req.URL.Host,
+		Route:     c.path,
+		Status:    status,
+		Attempt:   exec.attempt,
+		Duration:  time.Since(started),
+		FromCache: fromCache != nil && *fromCache,
+		Err:       err,
+	})
+}
@@ -0,0 +1,36 @@
+package reqx
+
+import (
+	"net/http"
+)
+
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+func (h *ClientBuilder) Use(middlewares ...Middleware) *ClientBuilder {
+	h.middlewares = append(h.middlewares, middlewares...)
+	return h
+}
+
+func (c *Client) do(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	next := RoundTripFunc(httpClient.Do)
+	if c.bodyReadTimeout > 0 {
+		next = c.limitBodyRead(next)
+	}
+	if c.trafficDump != nil {
+		next = c.dumpTraffic(next)
+	}
+	if c.har != nil {
+		next = c.recordHAR(next)
+	}
+	next = c.dumpDebug(next)
+	if c.httpCache != nil {
+		next = c.httpCache.roundTrip(next)
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		next = c.middlewares[i](next)
+	}
+
+	return next(req)
+}
@@ -1,9 +1,17 @@
 package reqx
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
 	"io"
-	"log/slog"
 	"mime/multipart"
+	"net/textproto"
+	"strings"
 )
 
 type MultipartFormBuilder struct {
@@ -43,9 +51,29 @@ func (m *MultipartFormBuilder) Do(successTarget any, errorTarget any) (*Response
 	return m.requestBuilder.Do(successTarget, errorTarget)
 }
 
+func (m *MultipartFormBuilder) OnProgress(fn func(progress MultipartProgress)) *MultipartFormBuilder {
+	m.formData.OnProgress = fn
+	return m
+}
+
+func (m *MultipartFormBuilder) Checksum(algorithm ChecksumAlgorithm) *MultipartFormBuilder {
+	m.formData.Checksum = algorithm
+	return m
+}
+
+func (m *MultipartFormBuilder) MaxSize(maxBytes int64) *MultipartFormBuilder {
+	m.formData.MaxSize = maxBytes
+	return m
+}
+
 func (b *RequestBuilder) buildMultipartForm(formData *MultipartFormData) (io.Reader, string, error) {
 	pipeReader, pipeWriter := io.Pipe()
-	writer := multipart.NewWriter(pipeWriter)
+
+	var out io.Writer = pipeWriter
+	if formData.MaxSize > 0 {
+		out = &sizeLimitWriter{w: pipeWriter, remaining: formData.MaxSize}
+	}
+	writer := multipart.NewWriter(out)
 	contentType := writer.FormDataContentType()
 
 	go func() {
@@ -54,15 +82,15 @@ func (b *RequestBuilder) buildMultipartForm(formData *MultipartFormData) (io.Rea
 			if writeErr != nil {
 				err := pipeWriter.CloseWithError(writeErr)
 				if err != nil {
-					slog.Error("Failed to close pipe writer",
-						"component", "buildMultipartForm",
+					b.log().Error("Failed to close pipe writer",
+						"package", "reqx",
 						"error", err)
 				}
 			} else {
 				err := pipeWriter.Close()
 				if err != nil {
-					slog.Error("Failed to close pipe writer",
-						"component", "buildMultipartForm",
+					b.log().Error("Failed to close pipe writer",
+						"package", "reqx",
 						"error", err)
 				}
 			}
@@ -76,18 +104,7 @@ func (b *RequestBuilder) buildMultipartForm(formData *MultipartFormData) (io.Rea
 		}
 
 		for _, file := range formData.Files {
-			part, err := writer.CreateFormFile(file.FieldName, file.FileName)
-			if err != nil {
-				writeErr = err
-				return
-			}
-
-			if file.Reader != nil {
-				_, writeErr = io.Copy(part, file.Reader)
-			} else if file.Data != nil {
-				_, writeErr = part.Write(file.Data)
-			}
-
+			writeErr = writeMultipartFile(writer, formData, file)
 			if writeErr != nil {
 				return
 			}
@@ -98,3 +115,147 @@ func (b *RequestBuilder) buildMultipartForm(formData *MultipartFormData) (io.Rea
 
 	return pipeReader, contentType, nil
 }
+
+func writeMultipartFile(writer *multipart.Writer, formData *MultipartFormData, file FormFile) error {
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+		quoteEscaper.Replace(file.FieldName), quoteEscaper.Replace(file.FileName)))
+	header.Set("Content-Type", "application/octet-stream")
+
+	content, size := file.content()
+
+	// The digest goes into the part header, so it is only sent when the
+	// content can be hashed before it is streamed.
+	var digest []byte
+	if formData.Checksum != "" {
+		if seeker, ok := content.(io.Seeker); ok {
+			start, err := seeker.Seek(0, io.SeekCurrent)
+			if err != nil {
+				return err
+			}
+			sum := formData.Checksum.hash()
+			if _, err := io.Copy(sum, content); err != nil {
+				return err
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return err
+			}
+			digest = sum.Sum(nil)
+			formData.Checksum.setHeader(header, digest)
+		}
+	}
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return err
+	}
+
+	progress := MultipartProgress{FieldName: file.FieldName, FileName: file.FileName, Size: size}
+	var sum hash.Hash
+	if formData.Checksum != "" && digest == nil {
+		sum = formData.Checksum.hash()
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := content.Read(buf)
+		if n > 0 {
+			if _, err := part.Write(buf[:n]); err != nil {
+				return err
+			}
+			if sum != nil {
+				sum.Write(buf[:n])
+			}
+			progress.Written += int64(n)
+			if formData.OnProgress != nil {
+				formData.OnProgress(progress)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	if sum != nil {
+		digest = sum.Sum(nil)
+	}
+	if formData.OnProgress != nil {
+		progress.Done = true
+		if digest != nil {
+			progress.Checksum = hex.EncodeToString(digest)
+		}
+		formData.OnProgress(progress)
+	}
+
+	return nil
+}
+
+func (f FormFile) content() (io.Reader, int64) {
+	if f.Reader == nil {
+		return bytes.NewReader(f.Data), int64(len(f.Data))
+	}
+
+	if seeker, ok := f.Reader.(io.Seeker); ok {
+		current, err := seeker.Seek(0, io.SeekCurrent)
+		end, endErr := seeker.Seek(0, io.SeekEnd)
+		if err == nil && endErr == nil {
+			if _, err := seeker.Seek(current, io.SeekStart); err == nil {
+				return f.Reader, end - current
+			}
+		}
+	}
+
+	return f.Reader, -1
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+type ChecksumAlgorithm string
+
+const (
+	ChecksumSHA256 ChecksumAlgorithm = "sha-256"
+	ChecksumMD5    ChecksumAlgorithm = "md5"
+)
+
+func (a ChecksumAlgorithm) hash() hash.Hash {
+	if a == ChecksumMD5 {
+		return md5.New()
+	}
+	return sha256.New()
+}
+
+func (a ChecksumAlgorithm) setHeader(header textproto.MIMEHeader, digest []byte) {
+	encoded := base64.StdEncoding.EncodeToString(digest)
+	if a == ChecksumMD5 {
+		header.Set("Content-MD5", encoded)
+		return
+	}
+	header.Set("Content-Digest", fmt.Sprintf("%s=:%s:", a, encoded))
+}
+
+type MultipartProgress struct {
+	FieldName string
+	FileName  string
+	Written   int64
+	Size      int64
+	Done      bool
+	Checksum  string
+}
+
+type sizeLimitWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (w *sizeLimitWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) > w.remaining {
+		return 0, ErrMultipartTooLarge
+	}
+
+	n, err := w.w.Write(p)
+	w.remaining -= int64(n)
+	return n, err
+}
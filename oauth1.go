@@ -1,34 +1,73 @@
 package reqx
 
 import (
+	"crypto"
 	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/base64"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
 	"net/url"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
+)
+
+type OAuth1SignatureMethod string
 
-	"github.com/google/uuid"
+const (
+	OAuth1HMACSHA1   OAuth1SignatureMethod = "HMAC-SHA1"
+	OAuth1HMACSHA256 OAuth1SignatureMethod = "HMAC-SHA256"
+	OAuth1RSASHA1    OAuth1SignatureMethod = "RSA-SHA1"
+	OAuth1Plaintext  OAuth1SignatureMethod = "PLAINTEXT"
 )
 
-func (b *RequestBuilder) generateOAuth1Header(method, fullURL string) (string, error) {
-	oauth := b.client.oauth1
+func (c *OAuth1Config) signatureMethod() OAuth1SignatureMethod {
+	if c.SignatureMethod == "" {
+		return OAuth1HMACSHA1
+	}
+
+	return c.SignatureMethod
+}
 
-	nonce := uuid.New().String()
+func (b *RequestBuilder) generateOAuth1Header(req *http.Request) (string, error) {
+	return oauth1Header(b.client.oauth1, req, b.client.newID(), nil)
+}
+
+func oauth1Header(oauth *OAuth1Config, req *http.Request, nonce string, extra map[string]string) (string, error) {
 	timestamp := strconv.FormatInt(time.Now().UTC().Unix(), 10)
 
 	params := map[string]string{
 		"oauth_consumer_key":     oauth.ConsumerKey,
 		"oauth_nonce":            nonce,
-		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_signature_method": string(oauth.signatureMethod()),
 		"oauth_timestamp":        timestamp,
 		"oauth_token":            oauth.AccessToken,
 		"oauth_version":          "1.0",
 	}
+	if oauth.AccessToken == "" {
+		delete(params, "oauth_token")
+	}
+	for k, v := range oauth.ExtraParams {
+		params[k] = v
+	}
+	for k, v := range extra {
+		params[k] = v
+	}
 
-	signature, err := b.generateOAuth1Signature(method, fullURL, params)
+	form, err := oauth1FormParams(req)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := oauth1Signature(oauth, req.Method, req.URL, params, form)
 	if err != nil {
 		return "", err
 	}
@@ -38,79 +77,133 @@ func (b *RequestBuilder) generateOAuth1Header(method, fullURL string) (string, e
 	var authParts []string
 	for key, value := range params {
 		builder.Reset()
-		builder.WriteString(key)
+		builder.WriteString(percentEncode(key))
 		builder.WriteString("=\"")
-		builder.WriteString(url.QueryEscape(value))
+		builder.WriteString(percentEncode(value))
 		builder.WriteString("\"")
 		authParts = append(authParts, builder.String())
 	}
 	sort.Strings(authParts)
 
+	if oauth.Realm != "" {
+		// The realm is sent in the header but is not part of the signature base string.
+		authParts = append([]string{`realm="` + percentEncode(oauth.Realm) + `"`}, authParts...)
+	}
+
 	builder.Reset()
 	builder.WriteString("OAuth ")
 	builder.WriteString(strings.Join(authParts, ", "))
 	return builder.String(), nil
 }
 
-func (b *RequestBuilder) generateOAuth1Signature(method, fullURL string, params map[string]string) (string, error) {
-	oauth := b.client.oauth1
+func oauth1FormParams(req *http.Request) (url.Values, error) {
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mediaType != "application/x-www-form-urlencoded" || req.GetBody == nil {
+		return nil, nil
+	}
 
-	u, err := url.Parse(fullURL)
+	body, err := req.GetBody()
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return url.ParseQuery(string(data))
+}
+
+func oauth1Signature(oauth *OAuth1Config, method string, u *url.URL, params map[string]string, form url.Values) (string, error) {
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if scheme == "http" && strings.HasSuffix(host, ":80") || scheme == "https" && strings.HasSuffix(host, ":443") {
+		host = host[:strings.LastIndex(host, ":")]
 	}
 
 	var baseURLBuilder strings.Builder
-	baseURLBuilder.WriteString(u.Scheme)
+	baseURLBuilder.WriteString(scheme)
 	baseURLBuilder.WriteString("://")
-	baseURLBuilder.WriteString(u.Host)
-	baseURLBuilder.WriteString(u.Path)
+	baseURLBuilder.WriteString(host)
+	baseURLBuilder.WriteString(u.EscapedPath())
 	baseURL := baseURLBuilder.String()
 
-	allParams := make(map[string]string)
+	var pairs []string
+	addPair := func(key, value string) {
+		pairs = append(pairs, percentEncode(key)+"="+percentEncode(value))
+	}
 	for k, v := range params {
-		allParams[k] = v
+		addPair(k, v)
 	}
 	for key, values := range u.Query() {
-		if len(values) > 0 {
-			allParams[key] = values[0]
+		for _, value := range values {
+			addPair(key, value)
 		}
 	}
-
-	var keys []string
-	for k := range allParams {
-		keys = append(keys, k)
+	for key, values := range form {
+		for _, value := range values {
+			addPair(key, value)
+		}
 	}
-	sort.Strings(keys)
+	// Encoded pairs sort by name and then by value, as RFC 5849 section 3.4.1.3.2 requires.
+	sort.Strings(pairs)
+	paramString := strings.Join(pairs, "&")
 
 	var builder strings.Builder
-	var paramParts []string
-	for _, key := range keys {
-		builder.Reset()
-		builder.WriteString(url.QueryEscape(key))
-		builder.WriteString("=")
-		builder.WriteString(url.QueryEscape(allParams[key]))
-		paramParts = append(paramParts, builder.String())
-	}
-	paramString := strings.Join(paramParts, "&")
-
-	builder.Reset()
-	builder.WriteString(url.QueryEscape(method))
+	builder.WriteString(percentEncode(strings.ToUpper(method)))
 	builder.WriteString("&")
-	builder.WriteString(url.QueryEscape(baseURL))
+	builder.WriteString(percentEncode(baseURL))
 	builder.WriteString("&")
-	builder.WriteString(url.QueryEscape(paramString))
+	builder.WriteString(percentEncode(paramString))
 	signatureBaseString := builder.String()
 
 	builder.Reset()
-	builder.WriteString(url.QueryEscape(oauth.ConsumerSecret))
+	builder.WriteString(percentEncode(oauth.ConsumerSecret))
 	builder.WriteString("&")
-	builder.WriteString(url.QueryEscape(oauth.AccessTokenSecret))
+	builder.WriteString(percentEncode(oauth.AccessTokenSecret))
 	signingKey := builder.String()
 
-	h := hmac.New(sha1.New, []byte(signingKey))
-	h.Write([]byte(signatureBaseString))
-	signature := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	switch oauth.signatureMethod() {
+	case OAuth1Plaintext:
+		return signingKey, nil
+	case OAuth1HMACSHA1:
+		return signHMAC(sha1.New, signingKey, signatureBaseString), nil
+	case OAuth1HMACSHA256:
+		return signHMAC(sha256.New, signingKey, signatureBaseString), nil
+	case OAuth1RSASHA1:
+		if oauth.PrivateKey == nil {
+			return "", ErrOAuth1PrivateKey
+		}
+		digest := sha1.Sum([]byte(signatureBaseString))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, oauth.PrivateKey, crypto.SHA1, digest[:])
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(signature), nil
+	default:
+		return "", ErrOAuth1Signature
+	}
+}
+
+func signHMAC(newHash func() hash.Hash, key string, message string) string {
+	h := hmac.New(newHash, []byte(key))
+	h.Write([]byte(message))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func percentEncode(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			builder.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", c)
+	}
 
-	return signature, nil
+	return builder.String()
 }
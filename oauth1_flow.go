@@ -0,0 +1,125 @@
+package reqx
+
+import (
+	"context"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type OAuth1FlowConfig struct {
+	ConsumerKey     string
+	ConsumerSecret  string
+	SignatureMethod OAuth1SignatureMethod
+	PrivateKey      *rsa.PrivateKey
+	Realm           string
+
+	RequestTokenURL string
+	AuthorizeURL    string
+	AccessTokenURL  string
+	CallbackURL     string
+}
+
+type OAuth1Credentials struct {
+	Token       string
+	TokenSecret string
+	Extra       url.Values
+}
+
+type OAuth1Flow struct {
+	client *Client
+	config OAuth1FlowConfig
+}
+
+func NewOAuth1Flow(client *Client, config OAuth1FlowConfig) *OAuth1Flow {
+	if client == nil {
+		client = NewClientBuilder().Build()
+	}
+
+	return &OAuth1Flow{client: client, config: config}
+}
+
+func (f *OAuth1Flow) RequestToken(ctx context.Context) (*OAuth1Credentials, error) {
+	callback := f.config.CallbackURL
+	if callback == "" {
+		callback = "oob"
+	}
+
+	credentials, err := f.exchange(ctx, f.config.RequestTokenURL, nil, map[string]string{"oauth_callback": callback})
+	if err != nil {
+		return nil, err
+	}
+	if f.config.CallbackURL != "" && credentials.Extra.Get("oauth_callback_confirmed") != "true" {
+		return nil, ErrOAuth1Callback
+	}
+
+	return credentials, nil
+}
+
+func (f *OAuth1Flow) AuthorizationURL(requestToken *OAuth1Credentials) string {
+	separator := "?"
+	if strings.Contains(f.config.AuthorizeURL, "?") {
+		separator = "&"
+	}
+
+	return f.config.AuthorizeURL + separator + url.Values{"oauth_token": {requestToken.Token}}.Encode()
+}
+
+func (f *OAuth1Flow) ParseCallback(query url.Values) (token string, verifier string) {
+	return query.Get("oauth_token"), query.Get("oauth_verifier")
+}
+
+func (f *OAuth1Flow) AccessToken(ctx context.Context, requestToken *OAuth1Credentials, verifier string) (*OAuth1Credentials, error) {
+	return f.exchange(ctx, f.config.AccessTokenURL, requestToken, map[string]string{"oauth_verifier": verifier})
+}
+
+func (f *OAuth1Flow) Config(accessToken *OAuth1Credentials) OAuth1Config {
+	return OAuth1Config{
+		ConsumerKey:       f.config.ConsumerKey,
+		ConsumerSecret:    f.config.ConsumerSecret,
+		AccessToken:       accessToken.Token,
+		AccessTokenSecret: accessToken.TokenSecret,
+		SignatureMethod:   f.config.SignatureMethod,
+		PrivateKey:        f.config.PrivateKey,
+		Realm:             f.config.Realm,
+	}
+}
+
+func (f *OAuth1Flow) exchange(ctx context.Context, endpoint string, token *OAuth1Credentials, extra map[string]string) (*OAuth1Credentials, error) {
+	if token == nil {
+		token = &OAuth1Credentials{}
+	}
+	config := f.Config(token)
+
+	resp, err := f.client.Post(endpoint).
+		Context(ctx).
+		Auth(AuthFunc(func(req *http.Request) error {
+			header, err := oauth1Header(&config, req, f.client.newID(), extra)
+			if err != nil {
+				return err
+			}
+			req.Header.Set("Authorization", header)
+			return nil
+		})).
+		DoRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(string(resp.Body))
+	if err != nil {
+		return nil, &DecodeError{Status: resp.Status, Body: resp.Body, Err: err}
+	}
+
+	credentials := &OAuth1Credentials{
+		Token:       values.Get("oauth_token"),
+		TokenSecret: values.Get("oauth_token_secret"),
+		Extra:       values,
+	}
+	if credentials.Token == "" {
+		return nil, &DecodeError{Status: resp.Status, Body: resp.Body, Err: ErrOAuth1Token}
+	}
+
+	return credentials, nil
+}
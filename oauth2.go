@@ -0,0 +1,325 @@
+package reqx
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type OAuth2Config struct {
+	ClientID     string
+	ClientSecret string
+	AuthURL      string
+	TokenURL     string
+	RedirectURL  string
+	Scopes       []string
+}
+
+type OAuth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Expiry       time.Time
+}
+
+type OAuth2ErrorPayload struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+type PKCEFlow struct {
+	client   *Client
+	config   OAuth2Config
+	verifier string
+	state    string
+}
+
+const (
+	tokenExpiryLeeway = 10 * time.Second
+	maxTokenErrorBody = 64 << 10
+)
+
+func NewPKCEFlow(client *Client, config OAuth2Config) (*PKCEFlow, error) {
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomURLString(16)
+	if err != nil {
+		return nil, err
+	}
+	if client == nil {
+		client = NewClientBuilder().Build()
+	}
+
+	return &PKCEFlow{
+		client:   client,
+		config:   config,
+		verifier: verifier,
+		state:    state,
+	}, nil
+}
+
+func (f *PKCEFlow) Verifier() string {
+	return f.verifier
+}
+
+func (f *PKCEFlow) Challenge() string {
+	sum := sha256.Sum256([]byte(f.verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (f *PKCEFlow) State() string {
+	return f.state
+}
+
+func (f *PKCEFlow) AuthCodeURL(extra ...FormField) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", f.config.ClientID)
+	if f.config.RedirectURL != "" {
+		params.Set("redirect_uri", f.config.RedirectURL)
+	}
+	if len(f.config.Scopes) > 0 {
+		params.Set("scope", strings.Join(f.config.Scopes, " "))
+	}
+	params.Set("state", f.state)
+	params.Set("code_challenge", f.Challenge())
+	params.Set("code_challenge_method", "S256")
+	for _, field := range extra {
+		params.Set(field.Name, field.Value)
+	}
+
+	separator := "?"
+	if strings.Contains(f.config.AuthURL, "?") {
+		separator = "&"
+	}
+
+	return f.config.AuthURL + separator + params.Encode()
+}
+
+func (f *PKCEFlow) ExchangeCallback(ctx context.Context, query url.Values) (*OAuth2TokenSource, error) {
+	if code := query.Get("error"); code != "" {
+		return nil, &APIError[OAuth2ErrorPayload]{
+			Payload: &OAuth2ErrorPayload{Code: code, Description: query.Get("error_description")},
+		}
+	}
+	if query.Get("state") != f.state {
+		return nil, ErrOAuth2State
+	}
+
+	return f.Exchange(ctx, query.Get("code"))
+}
+
+func (f *PKCEFlow) Exchange(ctx context.Context, code string) (*OAuth2TokenSource, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("code_verifier", f.verifier)
+	if f.config.RedirectURL != "" {
+		form.Set("redirect_uri", f.config.RedirectURL)
+	}
+
+	source := &OAuth2TokenSource{client: f.client, config: f.config}
+	token, err := source.request(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	source.token = token
+
+	return source, nil
+}
+
+type OAuth2TokenSource struct {
+	client *Client
+	config OAuth2Config
+
+	mu    sync.Mutex
+	token *OAuth2Token
+}
+
+func (s *OAuth2TokenSource) Token(ctx context.Context) (string, error) {
+	token, err := s.ExpiringToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return token.Value, nil
+}
+
+func (s *OAuth2TokenSource) ExpiringToken(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.valid() {
+		return &Token{Value: s.token.AccessToken, Expiry: s.token.Expiry}, nil
+	}
+
+	return s.refresh(ctx)
+}
+
+func (s *OAuth2TokenSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.refresh(ctx)
+	return err
+}
+
+func (s *OAuth2TokenSource) refresh(ctx context.Context) (*Token, error) {
+	if s.token.RefreshToken == "" {
+		return nil, ErrTokenExpired
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", s.token.RefreshToken)
+
+	token, err := s.request(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = s.token.RefreshToken
+	}
+	s.token = token
+
+	return &Token{Value: token.AccessToken, Expiry: token.Expiry}, nil
+}
+
+func (s *OAuth2TokenSource) OAuth2Token() OAuth2Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.token
+}
+
+func (s *OAuth2TokenSource) request(ctx context.Context, form url.Values) (*OAuth2Token, error) {
+	form.Set("client_id", s.config.ClientID)
+	if s.config.ClientSecret != "" {
+		form.Set("client_secret", s.config.ClientSecret)
+	}
+
+	return requestOAuth2Token(ctx, s.client, s.config.TokenURL, form)
+}
+
+func requestOAuth2Token(ctx context.Context, client *Client, tokenURL string, form url.Values) (*OAuth2Token, error) {
+	rb := client.Post(tokenURL).
+		Context(ctx).
+		FormUrlencodedContentType().
+		Header("Accept", "application/json").
+		Body(form)
+
+	token, _, err := Do[OAuth2Token, OAuth2ErrorPayload](rb)
+	if err != nil {
+		return nil, err
+	}
+	if token.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
+
+func (t *OAuth2Token) valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+
+	return t.Expiry.IsZero() || time.Until(t.Expiry) > tokenExpiryLeeway
+}
+
+func (h *ClientBuilder) TokenSource(source TokenSource) *ClientBuilder {
+	return h.Auth(TokenSourceProvider{Source: source})
+}
+
+func (h *ClientBuilder) TokenExpiredWhen(detect func(resp *Response) bool) *ClientBuilder {
+	h.tokenExpired = detect
+	return h
+}
+
+// A refresh is attempted at most once per request, so a server that keeps
+// rejecting fresh tokens cannot cause a loop.
+func (c *RequestBuilder) refreshAfterUnauthorized(exec *execution, resp *http.Response, sent time.Time) bool {
+	provider, ok := c.authProvider().(TokenSourceProvider)
+	if !ok {
+		return false
+	}
+
+	source, ok := provider.Source.(RefreshableTokenSource)
+	if !ok || exec.refreshed || !c.tokenExpired(resp) || !c.rewindBody() {
+		return false
+	}
+	exec.refreshed = true
+
+	if err := c.client.refreshes.refresh(exec.ctx, source, sent); err != nil {
+		c.log().Debug("token refresh after 401 failed",
+			"package", "reqx",
+			"method", string(c.method),
+			"path", c.path,
+			"error", err,
+		)
+		return false
+	}
+
+	return true
+}
+
+// Without a detector any 401 counts, unless the server names a bearer
+// error other than invalid_token (RFC 6750).
+func (c *RequestBuilder) tokenExpired(resp *http.Response) bool {
+	detect := c.client.tokenExpired
+	if detect == nil {
+		if resp.StatusCode != http.StatusUnauthorized {
+			return false
+		}
+		code := bearerError(resp.Header.Values("WWW-Authenticate"))
+		return code == "" || code == "invalid_token"
+	}
+
+	peeked, _ := io.ReadAll(io.LimitReader(resp.Body, maxTokenErrorBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peeked), resp.Body), resp.Body}
+
+	response := c.newResponse(resp)
+	response.Body = peeked
+	return detect(response)
+}
+
+func bearerError(challenges []string) string {
+	for _, challenge := range challenges {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			continue
+		}
+
+		for _, param := range strings.Split(params, ",") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(key, "error") {
+				return strings.Trim(value, `"`)
+			}
+		}
+	}
+
+	return ""
+}
+
+func randomURLString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,73 @@
+package reqx
+
+import (
+	"errors"
+	"maps"
+	"strings"
+)
+
+var ErrStopPagination = errors.New("reqx.stop_pagination")
+
+func (r *Response) NextLink() string {
+	return r.Link("next")
+}
+
+func (r *Response) Link(rel string) string {
+	for _, header := range r.Headers.Values("Link") {
+		for _, part := range strings.Split(header, ",") {
+			segments := strings.Split(part, ";")
+			target := strings.TrimSpace(segments[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			for _, param := range segments[1:] {
+				key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(key, "rel") {
+					continue
+				}
+
+				for _, candidate := range strings.Fields(strings.Trim(value, `"`)) {
+					if strings.EqualFold(candidate, rel) {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func (c *RequestBuilder) Paginate(fn func(page *Response) error) error {
+	page := c
+	for {
+		resp, err := page.DoRaw()
+		if err != nil {
+			return err
+		}
+
+		if err := fn(resp); err != nil {
+			if errors.Is(err, ErrStopPagination) {
+				return nil
+			}
+			return err
+		}
+
+		next := resp.NextLink()
+		if next == "" {
+			return nil
+		}
+
+		page = c.clone()
+		page.path = next
+		page.queryParams = make(map[string]string)
+	}
+}
+
+func (c *RequestBuilder) clone() *RequestBuilder {
+	clone := *c
+	clone.queryParams = maps.Clone(c.queryParams)
+	clone.headers = maps.Clone(c.headers)
+	return &clone
+}
@@ -0,0 +1,55 @@
+package reqx
+
+import (
+	"net/http"
+)
+
+type Phase int
+
+const (
+	PhaseBuild Phase = iota
+	PhaseAuth
+	PhaseSign
+	PhaseSend
+	phaseCount
+)
+
+type RequestMiddleware func(req *http.Request) error
+
+type ResponseTransformer func(resp *Response) error
+
+func (h *ClientBuilder) OnBuild(middlewares ...RequestMiddleware) *ClientBuilder {
+	return h.onPhase(PhaseBuild, middlewares)
+}
+
+func (h *ClientBuilder) OnAuth(middlewares ...RequestMiddleware) *ClientBuilder {
+	return h.onPhase(PhaseAuth, middlewares)
+}
+
+func (h *ClientBuilder) OnSign(middlewares ...RequestMiddleware) *ClientBuilder {
+	return h.onPhase(PhaseSign, middlewares)
+}
+
+func (h *ClientBuilder) OnSend(middlewares ...RequestMiddleware) *ClientBuilder {
+	return h.onPhase(PhaseSend, middlewares)
+}
+
+func (h *ClientBuilder) TransformResponse(transformers ...ResponseTransformer) *ClientBuilder {
+	h.transformers = append(h.transformers, transformers...)
+	return h
+}
+
+func (h *ClientBuilder) onPhase(phase Phase, middlewares []RequestMiddleware) *ClientBuilder {
+	h.phases[phase] = append(h.phases[phase], middlewares...)
+	return h
+}
+
+func (c *Client) runPhase(phase Phase, req *http.Request) error {
+	for _, middleware := range c.phases[phase] {
+		if err := middleware(req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignPhaseSeesPerAttemptHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	var signed http.Header
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		AttemptHeader("X-Attempt").
+		IdempotencyKeyHeader("Idempotency-Key").
+		DeadlineHeader("X-Deadline", DeadlineFormatMillis).
+		ConditionalRequests().
+		OnSign(func(req *http.Request) error {
+			signed = req.Header.Clone()
+			return nil
+		}).
+		Build()
+	defer client.Close()
+
+	if _, err := client.Get("/resource").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Get("/resource").Timeout(time.Minute).DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"X-Attempt", "Idempotency-Key", "X-Deadline", "If-None-Match"} {
+		if signed.Get(name) == "" {
+			t.Errorf("%s was not set before the sign phase", name)
+		}
+	}
+}
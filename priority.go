@@ -0,0 +1,62 @@
+package reqx
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultUrgency = 3
+	maxUrgency     = 7
+)
+
+func (c *RequestBuilder) Priority(urgency int, incremental bool) *RequestBuilder {
+	urgency = min(max(urgency, 0), maxUrgency)
+
+	var params []string
+	if urgency != defaultUrgency {
+		params = append(params, "u="+strconv.Itoa(urgency))
+	}
+	if incremental {
+		params = append(params, "i")
+	}
+	c.deleteHeader("Priority")
+	if len(params) > 0 {
+		c.headers["Priority"] = strings.Join(params, ", ")
+	}
+	return c
+}
+
+func (c *RequestBuilder) deleteHeader(name string) {
+	for key := range c.headers {
+		if strings.EqualFold(key, name) {
+			delete(c.headers, key)
+		}
+	}
+}
+
+// urgency reads the Priority header set on the request or the client, so
+// headers set by hand order the queue as well.
+func (c *RequestBuilder) urgency() int {
+	for _, headers := range []map[string]string{c.headers, c.client.headers} {
+		for key, value := range headers {
+			if strings.EqualFold(key, "Priority") {
+				return parseUrgency(value)
+			}
+		}
+	}
+	return defaultUrgency
+}
+
+func parseUrgency(value string) int {
+	for _, member := range strings.Split(value, ",") {
+		key, param, _ := strings.Cut(strings.TrimSpace(member), "=")
+		if key != "u" {
+			continue
+		}
+		if urgency, err := strconv.Atoi(strings.TrimSpace(param)); err == nil && urgency >= 0 && urgency <= maxUrgency {
+			return urgency
+		}
+	}
+	return defaultUrgency
+}
@@ -0,0 +1,46 @@
+package reqx
+
+import (
+	"context"
+	"net/http"
+)
+
+type inboundHeadersKey struct{}
+
+func ContextWithInboundHeaders(ctx context.Context, inbound *http.Request) context.Context {
+	return context.WithValue(ctx, inboundHeadersKey{}, inbound.Header.Clone())
+}
+
+func InboundHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(ContextWithInboundHeaders(r.Context(), r)))
+	})
+}
+
+func (h *ClientBuilder) PropagateHeaders(names ...string) *ClientBuilder {
+	for _, name := range names {
+		h.propagatedHeaders = append(h.propagatedHeaders, http.CanonicalHeaderKey(name))
+	}
+	return h
+}
+
+// Headers set on the client or the request win over propagated ones.
+func (c *RequestBuilder) propagateHeaders(ctx context.Context, req *http.Request) {
+	if len(c.client.propagatedHeaders) == 0 {
+		return
+	}
+
+	inbound, ok := ctx.Value(inboundHeadersKey{}).(http.Header)
+	if !ok {
+		return
+	}
+
+	for _, name := range c.client.propagatedHeaders {
+		if c.noAuth && c.isCredentialHeader(name) {
+			continue
+		}
+		if values := inbound.Values(name); len(values) > 0 && req.Header.Get(name) == "" {
+			req.Header[name] = append([]string(nil), values...)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package reqx
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type QuotaKind string
+
+const (
+	QuotaRequests     QuotaKind = "requests"
+	QuotaEgressBytes  QuotaKind = "egress_bytes"
+	QuotaIngressBytes QuotaKind = "ingress_bytes"
+)
+
+type QuotaExceededError struct {
+	Kind    QuotaKind
+	Limit   int64
+	Used    int64
+	Window  time.Duration
+	ResetAt time.Time
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("reqx.quota_exceeded: %s %d/%d per %s, resets at %s",
+		e.Kind, e.Used, e.Limit, e.Window, e.ResetAt.Format(time.RFC3339))
+}
+
+func (e *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+type QuotaUsage struct {
+	Kind    QuotaKind
+	Limit   int64
+	Used    int64
+	Window  time.Duration
+	ResetAt time.Time
+}
+
+type quota struct {
+	kind   QuotaKind
+	limit  int64
+	window time.Duration
+
+	mu    sync.Mutex
+	used  int64
+	start time.Time
+}
+
+type quotas struct {
+	requests *quota
+	egress   *quota
+	ingress  *quota
+}
+
+func (h *ClientBuilder) RequestQuota(max int64, window time.Duration) *ClientBuilder {
+	h.quotas.requests = &quota{kind: QuotaRequests, limit: max, window: window}
+	return h
+}
+
+func (h *ClientBuilder) EgressQuota(maxBytes int64, window time.Duration) *ClientBuilder {
+	h.quotas.egress = &quota{kind: QuotaEgressBytes, limit: maxBytes, window: window}
+	return h
+}
+
+func (h *ClientBuilder) IngressQuota(maxBytes int64, window time.Duration) *ClientBuilder {
+	h.quotas.ingress = &quota{kind: QuotaIngressBytes, limit: maxBytes, window: window}
+	return h
+}
+
+func (c *Client) QuotaUsage() []QuotaUsage {
+	var usage []QuotaUsage
+	for _, q := range []*quota{c.quotas.requests, c.quotas.egress, c.quotas.ingress} {
+		if q != nil {
+			usage = append(usage, q.usage())
+		}
+	}
+
+	return usage
+}
+
+// Bodies of known length are charged up front. Streamed, chunked and
+// multipart bodies are charged as the transport reads them, once the
+// remaining budget has been checked.
+func (q *quotas) reserve(req *http.Request) error {
+	if err := q.ingress.take(0); err != nil {
+		return err
+	}
+	if err := q.requests.take(1); err != nil {
+		return err
+	}
+
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	unknownLength := hasBody && req.ContentLength <= 0
+	size := req.ContentLength
+	if !hasBody || unknownLength {
+		size = 0
+	}
+	if err := q.egress.take(size); err != nil {
+		q.requests.add(-1)
+		return err
+	}
+	if unknownLength && q.egress != nil {
+		req.Body = &countingBody{ReadCloser: req.Body, quota: q.egress}
+	}
+
+	return nil
+}
+
+func (q *quotas) receive(body io.ReadCloser) io.ReadCloser {
+	if q.ingress == nil {
+		return body
+	}
+
+	return &countingBody{ReadCloser: body, quota: q.ingress}
+}
+
+type countingBody struct {
+	io.ReadCloser
+	quota *quota
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.quota.add(int64(n))
+	return n, err
+}
+
+func (q *quota) take(n int64) error {
+	if q == nil {
+		return nil
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(time.Now())
+	if q.used+n > q.limit || n == 0 && q.used >= q.limit {
+		return &QuotaExceededError{
+			Kind:    q.kind,
+			Limit:   q.limit,
+			Used:    q.used,
+			Window:  q.window,
+			ResetAt: q.start.Add(q.window),
+		}
+	}
+
+	q.used += n
+	return nil
+}
+
+func (q *quota) add(n int64) {
+	if q == nil {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.roll(time.Now())
+	q.used += n
+}
+
+func (q *quota) roll(now time.Time) {
+	if q.start.IsZero() || now.Sub(q.start) >= q.window {
+		q.start = now
+		q.used = 0
+	}
+}
+
+func (q *quota) usage() QuotaUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	usage := QuotaUsage{Kind: q.kind, Limit: q.limit, Window: q.window}
+	if !q.start.IsZero() && time.Since(q.start) < q.window {
+		usage.Used = q.used
+		usage.ResetAt = q.start.Add(q.window)
+	}
+
+	return usage
+}
@@ -0,0 +1,63 @@
+package reqx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuotasCountStreamedBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		w.Write(bytes.Repeat([]byte("x"), 300))
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		EgressQuota(1000, time.Hour).
+		IngressQuota(1000, time.Hour).
+		Build()
+	defer client.Close()
+
+	if _, err := client.Post("/upload").BodyFactory(chunked(400)).DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Get("/download").DoStream()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.Copy(io.Discard, response.BodyReader)
+	response.BodyReader.Close()
+
+	used := map[QuotaKind]int64{}
+	for _, usage := range client.QuotaUsage() {
+		used[usage.Kind] = usage.Used
+	}
+	if used[QuotaEgressBytes] != 400 {
+		t.Errorf("egress = %d, want 400", used[QuotaEgressBytes])
+	}
+	if used[QuotaIngressBytes] != 600 {
+		t.Errorf("ingress = %d, want 600", used[QuotaIngressBytes])
+	}
+
+	if _, err := client.Post("/upload").BodyFactory(chunked(600)).DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Post("/upload").BodyFactory(chunked(1)).DoRaw()
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("err = %v, want ErrQuotaExceeded once the egress budget is spent", err)
+	}
+}
+
+// The reader hides its length, so the request goes out chunked.
+func chunked(size int) func() io.Reader {
+	return func() io.Reader {
+		return io.MultiReader(strings.NewReader(strings.Repeat("y", size)))
+	}
+}
@@ -0,0 +1,161 @@
+package reqx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+type ContentRange struct {
+	Start int64
+	End   int64
+	Size  int64
+}
+
+func (r ContentRange) Length() int64 {
+	if r.Start < 0 {
+		return 0
+	}
+	return r.End - r.Start + 1
+}
+
+type RangePart struct {
+	ContentRange
+	ContentType string
+	Body        []byte
+}
+
+func (c *RequestBuilder) Range(start, end int64) *RequestBuilder {
+	return c.Ranges(ByteRange{Start: start, End: end})
+}
+
+// A negative End requests everything from Start on; a negative Start
+// requests the last -Start bytes.
+func (c *RequestBuilder) Ranges(ranges ...ByteRange) *RequestBuilder {
+	specs := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		switch {
+		case r.Start < 0:
+			specs = append(specs, strconv.FormatInt(r.Start, 10))
+		case r.End < 0:
+			specs = append(specs, fmt.Sprintf("%d-", r.Start))
+		default:
+			specs = append(specs, fmt.Sprintf("%d-%d", r.Start, r.End))
+		}
+	}
+
+	c.headers["Range"] = "bytes=" + strings.Join(specs, ",")
+	return c
+}
+
+func ParseContentRange(header string) (ContentRange, error) {
+	invalid := fmt.Errorf("%w: %q", ErrInvalidContentRange, header)
+
+	unit, spec, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(unit, "bytes") {
+		return ContentRange{}, invalid
+	}
+	span, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return ContentRange{}, invalid
+	}
+
+	parsed := ContentRange{Start: -1, End: -1, Size: -1}
+	if size != "*" {
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil || n < 0 {
+			return ContentRange{}, invalid
+		}
+		parsed.Size = n
+	}
+
+	if span == "*" {
+		if parsed.Size < 0 {
+			return ContentRange{}, invalid
+		}
+		return parsed, nil
+	}
+
+	first, last, ok := strings.Cut(span, "-")
+	if !ok {
+		return ContentRange{}, invalid
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return ContentRange{}, invalid
+	}
+	end, err := strconv.ParseInt(last, 10, 64)
+	if err != nil || start < 0 || end < start || (parsed.Size >= 0 && end >= parsed.Size) {
+		return ContentRange{}, invalid
+	}
+
+	parsed.Start, parsed.End = start, end
+	return parsed, nil
+}
+
+// Servers may ignore the Range header and answer 200 with the whole
+// representation, which is returned as a single part.
+func (r *Response) RangeParts() ([]RangePart, error) {
+	switch r.Status {
+	case http.StatusOK:
+		size := int64(len(r.Body))
+		return []RangePart{{
+			ContentRange: ContentRange{Start: 0, End: size - 1, Size: size},
+			ContentType:  r.Headers.Get("Content-Type"),
+			Body:         r.Body,
+		}}, nil
+	case http.StatusPartialContent:
+	default:
+		return nil, &HTTPError{Status: r.Status, Body: r.Body}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(r.Headers.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/byteranges" {
+		contentRange, err := ParseContentRange(r.Headers.Get("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+		return []RangePart{{
+			ContentRange: contentRange,
+			ContentType:  r.Headers.Get("Content-Type"),
+			Body:         r.Body,
+		}}, nil
+	}
+
+	var parts []RangePart
+	reader := multipart.NewReader(bytes.NewReader(r.Body), params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return parts, err
+		}
+
+		contentRange, err := ParseContentRange(part.Header.Get("Content-Range"))
+		if err != nil {
+			return parts, err
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return parts, err
+		}
+
+		parts = append(parts, RangePart{
+			ContentRange: contentRange,
+			ContentType:  part.Header.Get("Content-Type"),
+			Body:         body,
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package reqx
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+func (h *ClientBuilder) RateLimit(rps float64, burst int) *ClientBuilder {
+	return h.Limiter(newTokenBucket(rps, burst))
+}
+
+func (h *ClientBuilder) Limiter(limiter Limiter) *ClientBuilder {
+	h.limiter = limiter
+	return h
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rps float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func (b *tokenBucket) setRate(rps float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.rate = rps
+}
+
+func (b *tokenBucket) currentRate() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.rate
+}
+
+func (b *tokenBucket) cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.burst, b.tokens+1)
+}
+
+func (b *tokenBucket) Wait(ctx context.Context) error {
+	if err := sleepContext(ctx, b.reserve()); err != nil {
+		b.cancel()
+		return err
+	}
+
+	return nil
+}
+
+type pathRateLimit struct {
+	method   Method
+	segments []string
+	bucket   *tokenBucket
+}
+
+func (h *ClientBuilder) PathRateLimit(method Method, pathTemplate string, rps float64, burst int) *ClientBuilder {
+	h.pathLimits = append(h.pathLimits, &pathRateLimit{
+		method:   method,
+		segments: splitPath(pathTemplate),
+		bucket:   newTokenBucket(rps, burst),
+	})
+	return h
+}
+
+func (l *pathRateLimit) matches(method Method, path string) bool {
+	if l.method != "" && l.method != method {
+		return false
+	}
+
+	segments := splitPath(path)
+	for i, pattern := range l.segments {
+		if pattern == "**" {
+			return true
+		}
+		if i >= len(segments) {
+			return false
+		}
+		if pattern == "*" || (strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}")) {
+			continue
+		}
+		if pattern != segments[i] {
+			return false
+		}
+	}
+
+	return len(segments) == len(l.segments)
+}
+
+func (c *RequestBuilder) waitPathLimits(ctx context.Context, path string) error {
+	for _, limit := range c.client.pathLimits {
+		if !limit.matches(c.method, path) {
+			continue
+		}
+		if err := limit.bucket.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *RequestBuilder) relativePath() string {
+	path := c.client.expandVariables(c.path)
+	if isAbsoluteUrl(path) {
+		if u, err := url.Parse(path); err == nil {
+			return u.Path
+		}
+	}
+
+	return path
+}
+
+func splitPath(path string) []string {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+
+	return strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/'
+	})
+}
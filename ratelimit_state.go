@@ -0,0 +1,174 @@
+package reqx
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type RateLimitStatus struct {
+	Host       string
+	Token      string
+	Limit      int
+	Remaining  int
+	Reset      time.Time
+	ObservedAt time.Time
+}
+
+type rateLimitKey struct {
+	host  string
+	token string
+}
+
+type rateLimitTracker struct {
+	gate bool
+
+	mu      sync.Mutex
+	windows map[rateLimitKey]*RateLimitStatus
+}
+
+func newRateLimitTracker(gate bool) *rateLimitTracker {
+	return &rateLimitTracker{
+		gate:    gate,
+		windows: make(map[rateLimitKey]*RateLimitStatus),
+	}
+}
+
+func (c *Client) RateLimitState() []RateLimitStatus {
+	c.rateLimits.mu.Lock()
+	defer c.rateLimits.mu.Unlock()
+
+	state := make([]RateLimitStatus, 0, len(c.rateLimits.windows))
+	for _, window := range c.rateLimits.windows {
+		state = append(state, *window)
+	}
+	slices.SortFunc(state, func(a, b RateLimitStatus) int {
+		return cmp.Or(cmp.Compare(a.Host, b.Host), cmp.Compare(a.Token, b.Token))
+	})
+
+	return state
+}
+
+func (t *rateLimitTracker) wait(ctx context.Context, req *http.Request) error {
+	if !t.gate {
+		return nil
+	}
+
+	t.mu.Lock()
+	window, ok := t.windows[rateLimitKeyFor(req)]
+	if !ok {
+		t.mu.Unlock()
+		return nil
+	}
+
+	var delay time.Duration
+	if window.remaining() <= 0 {
+		delay = time.Until(window.Reset)
+	}
+	window.Remaining--
+	t.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	return sleepContext(ctx, delay)
+}
+
+// Windows are tracked for RateLimitState on every client; only wait depends
+// on RateLimitAware.
+func (t *rateLimitTracker) observe(req *http.Request, headers http.Header) {
+	status, ok := parseRateLimitHeaders(headers, time.Now())
+	if !ok {
+		return
+	}
+
+	key := rateLimitKeyFor(req)
+	status.Host = key.host
+	status.Token = key.token
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.windows[key] = status
+	t.prune(status.ObservedAt)
+}
+
+// Windows whose reset has passed carry no information any more.
+func (t *rateLimitTracker) prune(now time.Time) {
+	for key, window := range t.windows {
+		if !window.Reset.IsZero() && now.After(window.Reset) {
+			delete(t.windows, key)
+		}
+	}
+}
+
+func (w *RateLimitStatus) remaining() int {
+	if !w.Reset.IsZero() && time.Now().After(w.Reset) {
+		return w.Limit
+	}
+
+	return w.Remaining
+}
+
+func rateLimitKeyFor(req *http.Request) rateLimitKey {
+	return rateLimitKey{host: req.URL.Host, token: credentialIdentityFrom(req.Context())}
+}
+
+func parseRateLimitHeaders(headers http.Header, now time.Time) (*RateLimitStatus, bool) {
+	fields := map[string]string{}
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		for _, name := range []string{"Limit", "Remaining", "Reset"} {
+			if value := headers.Get(prefix + name); value != "" {
+				fields[strings.ToLower(name)] = value
+			}
+		}
+		if len(fields) > 0 {
+			break
+		}
+	}
+
+	if combined := headers.Get("RateLimit"); combined != "" && len(fields) == 0 {
+		for _, part := range strings.Split(combined, ",") {
+			name, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+			if !ok {
+				continue
+			}
+			switch name = strings.ToLower(name); name {
+			case "limit", "remaining", "reset":
+				fields[name] = value
+			case "r":
+				fields["remaining"] = value
+			case "t":
+				fields["reset"] = value
+			}
+		}
+	}
+
+	remaining, err := strconv.Atoi(fields["remaining"])
+	if err != nil {
+		return nil, false
+	}
+
+	status := &RateLimitStatus{Remaining: remaining, ObservedAt: now}
+	status.Limit, _ = strconv.Atoi(fields["limit"])
+	if reset, err := strconv.ParseInt(fields["reset"], 10, 64); err == nil {
+		status.Reset = rateLimitReset(reset, now)
+	}
+
+	return status, true
+}
+
+func rateLimitReset(value int64, now time.Time) time.Time {
+	// Values this small cannot be an epoch timestamp, so they are delta-seconds as in the RateLimit draft.
+	if value < 1_000_000_000 {
+		return now.Add(time.Duration(value) * time.Second)
+	}
+
+	return time.Unix(value, 0)
+}
@@ -0,0 +1,112 @@
+package reqx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingTokenSource struct {
+	calls atomic.Int64
+}
+
+func (s *countingTokenSource) Token(context.Context) (string, error) {
+	return "token-" + strconv.FormatInt(s.calls.Add(1), 10), nil
+}
+
+func rateLimitServer(t *testing.T, reset string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "50")
+		w.Header().Set("X-RateLimit-Reset", reset)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRateLimitWindowsAreKeyedByConfiguredCredentials(t *testing.T) {
+	server := rateLimitServer(t, "60")
+
+	for name, builder := range map[string]*ClientBuilder{
+		"token source": NewClientBuilder().TokenSource(&countingTokenSource{}),
+		"oauth1":       NewClientBuilder().OAuth1("consumer", "secret", "token", "token-secret"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			client := builder.BaseUrl(server.URL).RateLimitAware().Build()
+			defer client.Close()
+
+			for range 5 {
+				if _, err := client.Get("/").DoRaw(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if state := client.RateLimitState(); len(state) != 1 {
+				t.Fatalf("got %d windows, want 1: %+v", len(state), state)
+			}
+		})
+	}
+}
+
+func TestRateLimitWindowsAreSeparatedPerCredential(t *testing.T) {
+	server := rateLimitServer(t, "60")
+	client := NewClientBuilder().BaseUrl(server.URL).RateLimitAware().Build()
+	defer client.Close()
+
+	for _, token := range []string{"a", "b", "a"} {
+		if _, err := client.Get("/").BearerAuth(token).DoRaw(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if state := client.RateLimitState(); len(state) != 2 {
+		t.Fatalf("got %d windows, want 2", len(state))
+	}
+}
+
+func TestRateLimitStateTrackedWithoutRateLimitAware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "60")
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	defer client.Close()
+
+	for range 2 {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := client.Get("/").Context(ctx).DoRaw()
+		cancel()
+		if err != nil {
+			t.Fatalf("request was held back without RateLimitAware: %v", err)
+		}
+	}
+	state := client.RateLimitState()
+	if len(state) != 1 || state[0].Limit != 100 || state[0].Remaining != 0 {
+		t.Fatalf("state = %+v, want one window with the observed values", state)
+	}
+}
+
+func TestRateLimitWindowsExpireAfterReset(t *testing.T) {
+	tracker := newRateLimitTracker(true)
+	expired := &RateLimitStatus{Reset: time.Now().Add(-time.Second)}
+	tracker.windows[rateLimitKey{host: "old"}] = expired
+
+	req := httptest.NewRequest(http.MethodGet, "http://api.example.com/", nil)
+	headers := http.Header{"X-Ratelimit-Remaining": {"1"}, "X-Ratelimit-Reset": {"60"}}
+	tracker.observe(req, headers)
+
+	if _, ok := tracker.windows[rateLimitKey{host: "old"}]; ok {
+		t.Fatal("expired window was kept")
+	}
+	if len(tracker.windows) != 1 {
+		t.Fatalf("got %d windows, want 1", len(tracker.windows))
+	}
+}
@@ -0,0 +1,154 @@
+package reqx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers"`
+	Body    []byte      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers"`
+	Body    []byte      `json:"body,omitempty"`
+}
+
+type Interaction struct {
+	Request    RecordedRequest  `json:"request"`
+	Response   RecordedResponse `json:"response"`
+	RecordedAt time.Time        `json:"recorded_at"`
+	Duration   time.Duration    `json:"duration"`
+}
+
+type Cassette struct {
+	mu           sync.Mutex
+	Interactions []Interaction `json:"interactions"`
+}
+
+func NewCassette() *Cassette {
+	return &Cassette{}
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := &Cassette{}
+	if err := json.Unmarshal(data, cassette); err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+}
+
+func (c *Cassette) Add(interaction Interaction) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.Interactions = append(c.Interactions, interaction)
+}
+
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+func (c *Client) RecordingProxy(cassette *Cassette) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodConnect {
+			http.Error(w, "reqx: CONNECT tunnels cannot be recorded", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		target := r.URL.RequestURI()
+		if r.URL.IsAbs() {
+			target = r.URL.String()
+		}
+
+		headers := r.Header.Clone()
+		for _, name := range hopByHopHeaders {
+			headers.Del(name)
+		}
+
+		// The proxied application sends its own credentials; the client's
+		// default headers and auth must not leak into the recording.
+		rb := c.NewRequestBuilder().
+			Context(r.Context()).
+			Method(Method(r.Method)).
+			Path(target)
+		rb.queryParams = make(map[string]string)
+		rb.noAuth = true
+		rb.forwarded = headers
+		if len(body) > 0 {
+			rb.body = body
+		}
+
+		started := time.Now()
+		resp, err := rb.DoRaw()
+		if err != nil && resp == nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		cassette.Add(Interaction{
+			Request: RecordedRequest{
+				Method:  r.Method,
+				URL:     c.redactURL(rb.buildUrl()),
+				Headers: c.redactHeaders(headers),
+				Body:    body,
+			},
+			Response: RecordedResponse{
+				Status:  resp.Status,
+				Headers: c.redactHeaders(resp.Headers),
+				Body:    resp.Body,
+			},
+			RecordedAt: started,
+			Duration:   time.Since(started),
+		})
+
+		for k, values := range resp.Headers {
+			w.Header()[k] = values
+		}
+		for _, name := range hopByHopHeaders {
+			w.Header().Del(name)
+		}
+		w.Header().Del("Content-Length")
+		w.WriteHeader(resp.Status)
+		_, _ = w.Write(resp.Body)
+	})
+}
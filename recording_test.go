@@ -0,0 +1,85 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestRecordingProxyForwardsCallerHeadersOnly(t *testing.T) {
+	upstream, captured := captureServer(t)
+
+	client := NewClientBuilder().
+		BaseUrl(upstream.URL).
+		BearerAuth("client-token").
+		Header("X-Default", "client").
+		OnSign(func(req *http.Request) error {
+			req.Header.Set("X-Signature", "client")
+			return nil
+		}).
+		Build()
+	defer client.Close()
+
+	proxy := httptest.NewServer(client.RecordingProxy(NewCassette()))
+	defer proxy.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, proxy.URL+"/resource", nil)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Accept", "text/plain")
+	req.Header.Set("Authorization", "Bearer caller-token")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if got := captured.header.Values("Accept"); !slices.Equal(got, []string{"application/json", "text/plain"}) {
+		t.Errorf("Accept = %q, want both values", got)
+	}
+	if got := captured.header.Get("Authorization"); got != "Bearer caller-token" {
+		t.Errorf("Authorization = %q, want the caller's credentials", got)
+	}
+	for _, name := range []string{"X-Default", "X-Signature"} {
+		if got := captured.header.Get(name); got != "" {
+			t.Errorf("%s = %q leaked into the proxied request", name, got)
+		}
+	}
+}
+
+func TestRecordingProxyRedactsCredentials(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "upstream-session"})
+	}))
+	defer upstream.Close()
+
+	client := NewClientBuilder().BaseUrl(upstream.URL).Build()
+	defer client.Close()
+
+	cassette := NewCassette()
+	proxy := httptest.NewServer(client.RecordingProxy(cassette))
+	defer proxy.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, proxy.URL+"/resource", nil)
+	req.Header.Set("Authorization", "Bearer caller-token")
+	req.Header.Set("Cookie", "session=caller-session")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	interactions := cassette.Interactions
+	if len(interactions) != 1 {
+		t.Fatalf("recorded %d interactions, want 1", len(interactions))
+	}
+	recorded := interactions[0]
+	for _, name := range []string{"Authorization", "Cookie"} {
+		if got := recorded.Request.Headers.Get(name); got != redacted {
+			t.Errorf("request %s = %q, want it redacted", name, got)
+		}
+	}
+	if got := recorded.Response.Headers.Get("Set-Cookie"); got != redacted {
+		t.Errorf("response Set-Cookie = %q, want it redacted", got)
+	}
+}
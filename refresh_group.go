@@ -0,0 +1,79 @@
+package reqx
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type refreshCall struct {
+	done chan struct{}
+	err  error
+}
+
+// refreshGroup coalesces token refreshes triggered by concurrent 401s, so
+// the token endpoint sees one refresh instead of one per failed request.
+type refreshGroup struct {
+	ctx context.Context
+
+	mu       sync.Mutex
+	inflight map[any]*refreshCall
+	last     map[any]time.Time
+}
+
+func newRefreshGroup(ctx context.Context) *refreshGroup {
+	return &refreshGroup{
+		ctx:      ctx,
+		inflight: make(map[any]*refreshCall),
+		last:     make(map[any]time.Time),
+	}
+}
+
+// A refresh that completed after the rejected request was sent already
+// replaced its token, so the request is simply replayed.
+func (g *refreshGroup) refresh(ctx context.Context, source RefreshableTokenSource, sent time.Time) error {
+	if !reflect.TypeOf(source).Comparable() {
+		return source.Refresh(ctx)
+	}
+
+	g.mu.Lock()
+	if refreshed, ok := g.last[source]; ok && refreshed.After(sent) {
+		g.mu.Unlock()
+		return nil
+	}
+	call, ok := g.inflight[source]
+	if !ok {
+		call = &refreshCall{done: make(chan struct{})}
+		g.inflight[source] = call
+		go g.run(context.WithoutCancel(ctx), source, call)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// The shared refresh outlives the request that started it, since other
+// requests wait for it too; only closing the client cancels it.
+func (g *refreshGroup) run(ctx context.Context, source RefreshableTokenSource, call *refreshCall) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(g.ctx, func() {
+		cancel(ErrClientClosed)
+	})
+	call.err = source.Refresh(ctx)
+	stop()
+	cancel(nil)
+
+	g.mu.Lock()
+	delete(g.inflight, source)
+	if call.err == nil {
+		g.last[source] = time.Now()
+	}
+	g.mu.Unlock()
+	close(call.done)
+}
@@ -0,0 +1,79 @@
+package reqx
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type blockingRefreshSource struct {
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (s *blockingRefreshSource) Token(context.Context) (string, error) {
+	return "token", nil
+}
+
+func (s *blockingRefreshSource) Refresh(ctx context.Context) error {
+	s.calls.Add(1)
+	select {
+	case <-s.release:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+func TestSharedRefreshSurvivesLeaderCancellation(t *testing.T) {
+	group := newRefreshGroup(context.Background())
+	source := &blockingRefreshSource{release: make(chan struct{})}
+	sent := time.Now()
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() { leader <- group.refresh(leaderCtx, source, sent) }()
+
+	for source.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	waiter := make(chan error, 1)
+	go func() { waiter <- group.refresh(context.Background(), source, sent) }()
+
+	cancelLeader()
+	if err := <-leader; !errors.Is(err, context.Canceled) {
+		t.Errorf("leader err = %v, want its own cancellation", err)
+	}
+
+	close(source.release)
+	if err := <-waiter; err != nil {
+		t.Errorf("waiter err = %v, want the shared refresh to finish", err)
+	}
+	if got := source.calls.Load(); got != 1 {
+		t.Errorf("Refresh ran %d times, want 1", got)
+	}
+}
+
+func TestSharedRefreshStopsWhenClientCloses(t *testing.T) {
+	clientCtx, closeClient := context.WithCancel(context.Background())
+	group := newRefreshGroup(clientCtx)
+	source := &blockingRefreshSource{release: make(chan struct{})}
+
+	result := make(chan error, 1)
+	go func() { result <- group.refresh(context.Background(), source, time.Now()) }()
+	for source.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	closeClient()
+	select {
+	case err := <-result:
+		if !errors.Is(err, ErrClientClosed) {
+			t.Errorf("err = %v, want ErrClientClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("refresh kept running after the client closed")
+	}
+}
@@ -0,0 +1,70 @@
+package reqx
+
+import (
+	"bytes"
+	"io"
+)
+
+const defaultReplayLimit = 1 << 20
+
+type bodyFactory func() io.Reader
+
+func (h *ClientBuilder) ReplayableBodyLimit(maxBytes int64) *ClientBuilder {
+	h.replayLimit = maxBytes
+	return h
+}
+
+func (c *RequestBuilder) BodyFactory(factory func() io.Reader) *RequestBuilder {
+	c.body = bodyFactory(factory)
+	c.replayBuffer = nil
+	return c
+}
+
+// Readers that cannot seek are buffered on the first attempt so retries
+// resend the full payload. Bodies over the limit are streamed once and are
+// not retried.
+func (c *RequestBuilder) readerBody(reader io.Reader) io.Reader {
+	if c.replayBuffer != nil {
+		return bytes.NewReader(c.replayBuffer)
+	}
+	if _, ok := reader.(io.Seeker); ok || c.client.replayLimit < 0 {
+		return reader
+	}
+
+	data, err := io.ReadAll(io.LimitReader(reader, c.client.replayLimit+1))
+	if err != nil || int64(len(data)) > c.client.replayLimit {
+		c.body = io.MultiReader(bytes.NewReader(data), reader)
+		return c.body.(io.Reader)
+	}
+
+	c.replayBuffer = data
+	return bytes.NewReader(data)
+}
+
+func (c *RequestBuilder) rewindBody() bool {
+	switch body := c.body.(type) {
+	case bodyFactory:
+		return true
+	case *MultipartFormData:
+		for _, file := range body.Files {
+			if file.Reader != nil && !rewindReader(file.Reader) {
+				return false
+			}
+		}
+		return true
+	case io.Reader:
+		return c.replayBuffer != nil || rewindReader(body)
+	default:
+		return true
+	}
+}
+
+func rewindReader(reader io.Reader) bool {
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return false
+	}
+
+	_, err := seeker.Seek(0, io.SeekStart)
+	return err == nil
+}
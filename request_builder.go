@@ -4,11 +4,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"log/slog"
+	"maps"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"strings"
+	"time"
 )
 
 func (c *Client) NewRequestBuilder() *RequestBuilder {
@@ -16,7 +20,7 @@ func (c *Client) NewRequestBuilder() *RequestBuilder {
 		client:      c,
 		method:      MethodGet,
 		path:        "",
-		queryParams: c.queryParams,
+		queryParams: maps.Clone(c.queryParams),
 		headers:     make(map[string]string),
 		contentType: ContentTypeJSON,
 		body:        nil,
@@ -65,14 +69,69 @@ func (c *RequestBuilder) Header(key string, value string) *RequestBuilder {
 
 func (c *RequestBuilder) Body(body any) *RequestBuilder {
 	c.body = body
+	c.replayBuffer = nil
 	return c
 }
 
 func (c *RequestBuilder) BodyReader(reader io.Reader) *RequestBuilder {
 	c.body = reader
+	c.replayBuffer = nil
 	return c
 }
 
+func (c *RequestBuilder) Context(ctx context.Context) *RequestBuilder {
+	c.context = ctx
+	return c
+}
+
+func (c *RequestBuilder) Timeout(timeout time.Duration) *RequestBuilder {
+	c.timeout = timeout
+	return c
+}
+
+func (c *RequestBuilder) Transport(transport http.RoundTripper) *RequestBuilder {
+	c.transport = transport
+	return c
+}
+
+func (c *RequestBuilder) Validate(validator ResponseValidator) *RequestBuilder {
+	c.validator = validator
+	return c
+}
+
+func (c *RequestBuilder) SuccessStatuses(codes ...int) *RequestBuilder {
+	c.successCodes = append(c.successCodes, codes...)
+	return c
+}
+
+func (c *RequestBuilder) Logger(logger *slog.Logger) *RequestBuilder {
+	c.logger = logger
+	return c
+}
+
+func (c *RequestBuilder) LogAttrs(attrs ...slog.Attr) *RequestBuilder {
+	c.logAttrs = append(c.logAttrs, attrs...)
+	return c
+}
+
+func (c *RequestBuilder) log() *slog.Logger {
+	logger := c.client.logger
+	if c.logger != nil {
+		logger = withLogLevel(c.logger, c.client.logLevel)
+	}
+
+	if len(c.logAttrs) == 0 {
+		return logger
+	}
+
+	args := make([]any, 0, len(c.logAttrs))
+	for _, attr := range c.logAttrs {
+		args = append(args, attr)
+	}
+
+	return logger.With(args...)
+}
+
 func (c *RequestBuilder) JsonContentType() *RequestBuilder {
 	c.contentType = ContentTypeJSON
 	return c
@@ -96,124 +155,446 @@ func (c *RequestBuilder) MultipartFormBody() *MultipartFormBuilder {
 }
 
 func (c *RequestBuilder) Do(successTarget any, errorTarget any) (*Response, error) {
-	return c.executeWithRetry(func() (*Response, error) {
-		url := c.buildUrl()
-		req, err := c.buildRequest(c.client.context, url)
-		if err != nil {
-			return nil, err
+	if err := c.client.require(CapAutoDecode); err != nil {
+		return nil, err
+	}
+
+	cacheKey, cacheable := c.decodedCacheKey(successTarget)
+	if cacheable {
+		if response, ok := c.client.decodedCache.load(cacheKey, successTarget); ok {
+			return response, nil
 		}
+	}
 
-		resp, err := c.client.client.Do(req)
-		if err != nil {
-			return nil, err
+	response, err := c.execute(false)
+
+	var validationErr *ValidationError
+	if response != nil && !errors.As(err, &validationErr) {
+		if decodeErr := c.decode(response, successTarget, errorTarget); decodeErr != nil && err == nil {
+			err = decodeErr
 		}
-		defer func() {
-			err := resp.Body.Close()
-			if err != nil {
-				slog.Error("Failed to close response body",
-					"component", "RequestBuilder",
-					"error", err)
-			}
-		}()
+	}
+
+	if cacheable && err == nil && response.IsSuccess() && len(response.Body) > 0 {
+		c.client.decodedCache.store(cacheKey, successTarget, response)
+	}
+
+	return response, err
+}
+
+func (c *RequestBuilder) DoRaw() (*Response, error) {
+	return c.execute(false)
+}
 
-		bodyBytes, err := io.ReadAll(resp.Body)
+func (c *RequestBuilder) DoStream() (*Response, error) {
+	return c.execute(true)
+}
+
+func (c *RequestBuilder) execute(stream bool) (*Response, error) {
+	ctx, done, err := c.client.begin(c.context)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		release := done
+		done = func() {
+			cancel()
+			release()
+		}
+	}
+
+	if c.client.bulkhead != nil {
+		releaseSlot, err := c.client.bulkhead.acquire(ctx, c.urgency())
 		if err != nil {
+			done()
 			return nil, err
 		}
+		release := done
+		done = func() {
+			releaseSlot()
+			release()
+		}
+	}
 
-		response := &Response{
-			Status:  resp.StatusCode,
-			Body:    bodyBytes,
-			Headers: resp.Header,
+	exec := &execution{ctx: ctx, stream: stream}
+	if c.client.idempotencyHeader != "" {
+		exec.idempotencyKey = c.client.newID()
+	}
+
+	if len(c.client.failover) > 0 {
+		c = c.clone()
+	}
+	c, target := c.routeCanary()
+	started := time.Now()
+
+	response, err := c.executeWithRetry(ctx, func() (*Response, error) {
+		exec.attempt++
+		return c.attempt(exec)
+	})
+
+	c.client.canary.observe(target, response, err, time.Since(started))
+	response, err = c.applyFallback(response, err)
+
+	if response != nil {
+		response.AttemptRequestIDs = exec.requestIDs
+	}
+
+	if teeErr := c.teeResponse(response); teeErr != nil && err == nil {
+		err = teeErr
+	}
+
+	if stream && response != nil && response.BodyReader != nil {
+		response.BodyReader = &trackedBody{ReadCloser: response.BodyReader, done: done}
+	} else {
+		done()
+	}
+
+	if response == nil {
+		return response, err
+	}
+	if stream {
+		if err == nil && !response.IsSuccess() {
+			err = streamError(response)
 		}
+		return response, err
+	}
 
-		if response.IsSuccess() {
-			if successTarget != nil {
-				if err := json.Unmarshal(response.Body, successTarget); err != nil {
-					slog.Error("failed to unmarshal success response",
-						"package", "reqx",
-						"error", err,
-					)
-				}
-			}
-		} else {
-			if errorTarget != nil {
-				if err := json.Unmarshal(response.Body, errorTarget); err != nil {
-					slog.Error("failed to unmarshal error response",
-						"package", "reqx",
-						"error", err,
-					)
-				}
-			}
+	c.mirror(exec, response)
+
+	for _, transform := range c.client.transformers {
+		if transformErr := transform(response); transformErr != nil {
+			return response, transformErr
 		}
+	}
 
-		return response, nil
-	})
+	if err == nil && unsuccessful(response) {
+		return response, &HTTPError{Status: response.Status, Body: response.Body}
+	}
+	if err == nil {
+		if validationErr := c.validate(response); validationErr != nil {
+			return response, validationErr
+		}
+	}
+
+	return response, err
 }
 
-func (c *RequestBuilder) DoRaw() (*Response, error) {
-	return c.executeWithRetry(func() (*Response, error) {
-		url := c.buildUrl()
-		req, err := c.buildRequest(c.client.context, url)
-		if err != nil {
+// A 304 is the expected answer to a conditional request, not a failure.
+func unsuccessful(response *Response) bool {
+	return !response.IsSuccess() && !response.IsNotModified()
+}
+
+// The body of a failed stream is read up to an excerpt and closed; the
+// excerpt stays readable through BodyReader for callers that inspect it.
+func streamError(response *Response) error {
+	if response.BodyReader != nil {
+		response.Body, _ = io.ReadAll(io.LimitReader(response.BodyReader, maxBodyExcerpt))
+		response.BodyReader.Close()
+		response.BodyReader = io.NopCloser(bytes.NewReader(response.Body))
+	}
+	return &HTTPError{Status: response.Status, Body: response.Body}
+}
+
+func (c *RequestBuilder) validate(response *Response) error {
+	if c.validator == nil || !response.IsSuccess() {
+		return nil
+	}
+
+	validateErr := c.validator.Validate(response.Body)
+	if validateErr == nil {
+		return nil
+	}
+
+	validationErr := &ValidationError{Status: response.Status, Body: response.Body, Err: validateErr}
+
+	var violations SchemaViolations
+	if errors.As(validateErr, &violations) {
+		validationErr.Violations = violations
+	}
+
+	return validationErr
+}
+
+func (c *RequestBuilder) attempt(exec *execution) (*Response, error) {
+	response, err := c.roundTrip(exec)
+	if exec.trial != "" {
+		c.client.breaker.release(exec.trial)
+		exec.trial = ""
+	}
+	return response, err
+}
+
+func (c *RequestBuilder) roundTrip(exec *execution) (*Response, error) {
+	ctx, stream := exec.ctx, exec.stream
+
+	rawURL := c.buildUrl()
+	resolvedURL, err := c.client.srv.rewrite(ctx, rawURL)
+	if err != nil {
+		return nil, c.client.transportError(ctx, string(c.method), rawURL, err)
+	}
+
+	url, urlAuth := c.stripURLCredentials(resolvedURL)
+	req, err := c.buildRequest(ctx, url, urlAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.client.firewall != nil {
+		if c.transport != nil {
+			return nil, &PolicyViolationError{URL: req.URL.Redacted(), Reason: "per-request transports bypass the firewall"}
+		}
+		if err := c.client.firewall.check(ctx, req.URL); err != nil {
 			return nil, err
 		}
+	}
+
+	if err := c.waitPathLimits(ctx, c.relativePath()); err != nil {
+		return nil, c.client.transportError(ctx, req.Method, url, err)
+	}
+
+	if c.client.limiter != nil {
+		if err := c.client.limiter.Wait(ctx); err != nil {
+			return nil, c.client.transportError(ctx, req.Method, url, err)
+		}
+	}
+
+	if c.client.throttle != nil {
+		if err := c.client.throttle.wait(ctx, req.URL.Host); err != nil {
+			return nil, c.client.transportError(ctx, req.Method, url, err)
+		}
+	}
 
-		resp, err := c.client.client.Do(req)
+	if err := c.client.rateLimits.wait(ctx, req); err != nil {
+		return nil, c.client.transportError(ctx, req.Method, url, err)
+	}
+
+	c.client.applyAttemptHeaders(exec, req)
+
+	if c.client.deadline != nil {
+		c.client.deadline.apply(ctx, req)
+	}
+
+	if c.client.conditional != nil && !stream && !exec.shadow {
+		c.client.conditional.prepare(req)
+	}
+
+	if err := c.authorizeRequest(req, urlAuth); err != nil {
+		return nil, err
+	}
+
+	if err := c.client.runPhase(PhaseSend, req); err != nil {
+		return nil, err
+	}
+
+	if c.client.breaker != nil && exec.admitted != exec.attempt {
+		trial, err := c.client.breaker.allow(req.URL.Host)
 		if err != nil {
 			return nil, err
 		}
-		defer func() {
-			err := resp.Body.Close()
-			if err != nil {
-				slog.Error("Failed to close response body",
-					"component", "RequestBuilder",
-					"error", err)
-			}
-		}()
+		if trial {
+			exec.trial = req.URL.Host
+		}
+		exec.admitted = exec.attempt
+	}
 
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
+	if !exec.shadow {
+		if err := c.client.quotas.reserve(req); err != nil {
 			return nil, err
 		}
+	}
+
+	if !exec.shadow {
+		c.client.hooks.request(req)
+	}
+
+	var fromCache *bool
+	if c.client.httpCache != nil {
+		req, fromCache = trackCacheHit(req)
+	}
+
+	if !exec.shadow {
+		req = c.withDebug(req)
+	}
 
-		response := &Response{
-			Status:  resp.StatusCode,
-			Body:    bodyBytes,
-			Headers: resp.Header,
+	trace := &timingTrace{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	started := time.Now()
+	trace.start = started
+	httpClient := c.httpClient()
+	if exec.freshConn {
+		exec.freshConn = false
+		httpClient = freshConnection(httpClient)
+	}
+	resp, err := c.client.do(httpClient, req)
+	if err != nil {
+		if c.retryReusedConnection(exec, req, err, trace) {
+			return c.roundTrip(exec)
+		}
+		if c.client.breaker != nil && ctx.Err() == nil {
+			c.client.breaker.record(req.URL.Host, nil, err)
+			exec.trial = ""
 		}
 
+		err = c.client.transportError(ctx, req.Method, url, err)
+		if !exec.shadow {
+			c.client.hooks.error(req, err)
+		}
+		c.observeMetrics(exec, req, started, 0, fromCache, err)
+		return nil, err
+	}
+
+	if c.client.breaker != nil {
+		c.client.breaker.record(req.URL.Host, resp, nil)
+		exec.trial = ""
+	}
+
+	c.client.rateLimits.observe(req, resp.Header)
+	if c.client.throttle != nil {
+		c.client.throttle.observe(req.URL.Host, resp.StatusCode)
+	}
+
+	if c.refreshAfterUnauthorized(exec, resp, started) {
+		c.observeMetrics(exec, req, started, resp.StatusCode, fromCache, nil)
+		resp.Body.Close()
+		return c.roundTrip(exec)
+	}
+
+	if c.client.requestIDHeader != "" {
+		exec.requestIDs = append(exec.requestIDs, resp.Header.Get(c.client.requestIDHeader))
+	}
+
+	if !exec.shadow {
+		resp.Body = c.client.quotas.receive(resp.Body)
+	}
+
+	if stream {
+		response := c.newResponse(resp)
+		response.BodyReader = resp.Body
+		response.FromCache = fromCache != nil && *fromCache
+		response.Timings = trace.timings(time.Now())
+		c.observeMetrics(exec, req, started, resp.StatusCode, fromCache, nil)
+		if !exec.shadow {
+			c.client.hooks.response(response)
+		}
 		return response, nil
-	})
-}
+	}
 
-func (c *RequestBuilder) DoStream() (*Response, error) {
-	return c.executeWithRetry(func() (*Response, error) {
-		url := c.buildUrl()
-		req, err := c.buildRequest(c.client.context, url)
+	defer func() {
+		err := resp.Body.Close()
 		if err != nil {
-			return nil, err
+			c.log().Error("Failed to close response body",
+				"package", "reqx",
+				"error", err)
 		}
+	}()
 
-		resp, err := c.client.client.Do(req)
-		if err != nil {
-			return nil, err
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		err = c.client.transportError(ctx, req.Method, url, err)
+		if !exec.shadow {
+			c.client.hooks.error(req, err)
 		}
+		c.observeMetrics(exec, req, started, resp.StatusCode, fromCache, err)
+		return nil, err
+	}
+	read := time.Now()
+	c.observeMetrics(exec, req, started, resp.StatusCode, fromCache, nil)
 
-		response := &Response{
-			Status:     resp.StatusCode,
-			Headers:    resp.Header,
-			BodyReader: resp.Body,
+	response := c.newResponse(resp)
+	response.Body = bodyBytes
+	response.FromCache = fromCache != nil && *fromCache
+	response.Timings = trace.timings(read)
+
+	// Error pages from proxies and load balancers are rarely protected;
+	// they keep their raw body so the status can be retried or reported.
+	if err := c.transformResponseBody(response); err != nil && response.IsSuccess() {
+		return nil, err
+	}
+
+	if c.client.conditional != nil && !exec.shadow {
+		response = c.client.conditional.resolve(req, response)
+	}
+
+	if c.client.hashBodies {
+		response.ContentHash = contentHash(response.Body)
+	}
+
+	if !exec.shadow {
+		c.client.hooks.response(response)
+	}
+
+	return response, nil
+}
+
+func (c *RequestBuilder) httpClient() *http.Client {
+	if c.transport == nil {
+		return c.client.client
+	}
+
+	httpClient := *c.client.client
+	httpClient.Transport = c.transport
+	return &httpClient
+}
+
+func (c *RequestBuilder) newResponse(resp *http.Response) *Response {
+	successCodes := make([]int, 0, len(c.client.successCodes)+len(c.successCodes))
+	successCodes = append(successCodes, c.client.successCodes...)
+	successCodes = append(successCodes, c.successCodes...)
+
+	return &Response{
+		Status:          resp.StatusCode,
+		Headers:         resp.Header,
+		ContentEncoding: resp.Header.Get("Content-Encoding"),
+		Uncompressed:    resp.Uncompressed,
+		successCodes:    successCodes,
+	}
+}
+
+func (c *RequestBuilder) decode(response *Response, successTarget any, errorTarget any) error {
+	if len(response.Body) == 0 || response.IsNotModified() {
+		return nil
+	}
+
+	if response.IsSuccess() {
+		if successTarget != nil {
+			if err := json.Unmarshal(response.Body, successTarget); err != nil {
+				c.log().Error("failed to unmarshal success response",
+					"package", "reqx",
+					"error", err,
+				)
+				return &DecodeError{Status: response.Status, Body: response.Body, Err: err}
+			}
+		}
+	} else {
+		if errorTarget != nil {
+			if err := json.Unmarshal(response.Body, errorTarget); err != nil {
+				c.log().Error("failed to unmarshal error response",
+					"package", "reqx",
+					"error", err,
+				)
+			}
 		}
+	}
 
-		return response, nil
-	})
+	return nil
 }
 
 func (c *RequestBuilder) buildUrl() string {
+	path := c.client.expandVariables(c.path)
+
 	var builder strings.Builder
-	builder.WriteString(c.client.baseUrl)
-	builder.WriteString(c.path)
+	if !isAbsoluteUrl(path) {
+		baseUrl := c.client.baseUrl
+		if c.baseUrl != "" {
+			baseUrl = c.baseUrl
+		}
+		builder.WriteString(c.client.expandVariables(baseUrl))
+	}
+	builder.WriteString(path)
 
 	u, _ := url.Parse(builder.String())
 	q := u.Query()
@@ -224,15 +605,32 @@ func (c *RequestBuilder) buildUrl() string {
 	return u.String()
 }
 
-func (b *RequestBuilder) buildRequest(ctx context.Context, fullURL string) (*http.Request, error) {
+func isAbsoluteUrl(path string) bool {
+	path = strings.TrimPrefix(path, srvSchemePrefix)
+	return strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://")
+}
+
+func (b *RequestBuilder) buildRequest(ctx context.Context, fullURL string, urlAuth AuthProvider) (*http.Request, error) {
+	ctx = withCredentialIdentity(ctx, b.credentialIdentity(b.requestAuth(urlAuth)))
+	ctx = withLogger(ctx, b.log())
+
 	var buf io.Reader
+	contentType := b.contentType
 	if b.body != nil {
 		switch body := b.body.(type) {
+		case bodyFactory:
+			buf = body()
 		case io.Reader:
-			buf = body
+			buf = b.readerBody(body)
 		case []byte:
+			if b.templateBody {
+				body = []byte(b.client.expandVariables(string(body)))
+			}
 			buf = bytes.NewReader(body)
 		case string:
+			if b.templateBody {
+				body = b.client.expandVariables(body)
+			}
 			buf = strings.NewReader(body)
 		default:
 			switch b.contentType {
@@ -247,18 +645,21 @@ func (b *RequestBuilder) buildRequest(ctx context.Context, fullURL string) (*htt
 				if !ok {
 					return nil, ErrInvalidBody
 				}
+				if b.templateBody {
+					form = b.client.expandForm(form)
+				}
 				buf = bytes.NewBufferString(form.Encode())
 			case ContentTypeMultipartForm:
 				formData, ok := body.(*MultipartFormData)
 				if !ok {
 					return nil, ErrInvalidBody
 				}
-				multipartBuf, contentType, err := b.buildMultipartForm(formData)
+				multipartBuf, multipartType, err := b.buildMultipartForm(formData)
 				if err != nil {
 					return nil, err
 				}
 				buf = multipartBuf
-				b.contentType = ContentType(contentType)
+				contentType = ContentType(multipartType)
 			}
 		}
 	}
@@ -267,31 +668,84 @@ func (b *RequestBuilder) buildRequest(ctx context.Context, fullURL string) (*htt
 	if err != nil {
 		return nil, err
 	}
-
-	if b.client.oauth1 != nil {
-		authHeader, err := b.generateOAuth1Header(string(b.method), fullURL)
-		if err != nil {
-			return nil, err
+	if factory, ok := b.body.(bodyFactory); ok {
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(factory()), nil
 		}
-		req.Header.Set("Authorization", authHeader)
 	}
 
+	if b.forwarded != nil {
+		req.Header = b.forwarded.Clone()
+	}
 	for k, v := range b.client.headers {
-		req.Header.Set(k, v)
+		if b.forwarded != nil || b.noAuth && b.isCredentialHeader(k) {
+			continue
+		}
+		if err := b.client.setHeader(req.Header, k, b.client.expandVariables(v)); err != nil {
+			return nil, err
+		}
 	}
 	for k, v := range b.headers {
-		req.Header.Set(k, v)
+		if err := b.client.setHeader(req.Header, k, v); err != nil {
+			return nil, err
+		}
 	}
+	b.propagateHeaders(ctx, req)
+	b.propagateTrace(ctx, req)
 
-	if b.body != nil {
+	if b.body != nil && b.forwarded == nil {
 		if b.client.contentType != "" {
 			req.Header.Set("Content-Type", string(b.client.contentType))
 		}
 
-		if b.contentType != "" {
-			req.Header.Set("Content-Type", string(b.contentType))
+		if contentType != "" {
+			req.Header.Set("Content-Type", string(contentType))
 		}
 	}
 
+	if err := b.transformRequestBody(req); err != nil {
+		return nil, err
+	}
+
+	if err := b.client.runPhase(PhaseBuild, req); err != nil {
+		return nil, err
+	}
+
 	return req, nil
 }
+
+func (b *RequestBuilder) requestAuth(urlAuth AuthProvider) AuthProvider {
+	provider := b.authProvider()
+	if provider == nil && !b.noAuth {
+		provider = urlAuth
+	}
+	return provider
+}
+
+// Authorization and signing run last, so signatures cover every header the
+// client adds to the attempt.
+func (b *RequestBuilder) authorizeRequest(req *http.Request, urlAuth AuthProvider) error {
+	if b.forwarded != nil {
+		return nil
+	}
+
+	if provider := b.requestAuth(urlAuth); provider != nil {
+		if err := provider.Apply(req); err != nil {
+			return err
+		}
+	}
+
+	if err := b.client.runPhase(PhaseAuth, req); err != nil {
+		return err
+	}
+
+	if b.client.oauth1 != nil && !b.noAuth {
+		authHeader, err := b.generateOAuth1Header(req)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", authHeader)
+	}
+
+	return b.client.runPhase(PhaseSign, req)
+}
@@ -0,0 +1,40 @@
+package reqxtest
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+type LatencyProfile interface {
+	Next(rng *rand.Rand) time.Duration
+}
+
+type LatencyFunc func(rng *rand.Rand) time.Duration
+
+func (f LatencyFunc) Next(rng *rand.Rand) time.Duration {
+	return f(rng)
+}
+
+func FixedLatency(d time.Duration) LatencyProfile {
+	return LatencyFunc(func(*rand.Rand) time.Duration {
+		return d
+	})
+}
+
+func NormalLatency(mean, stddev time.Duration) LatencyProfile {
+	return LatencyFunc(func(rng *rand.Rand) time.Duration {
+		d := time.Duration(rng.NormFloat64()*float64(stddev)) + mean
+		if d < 0 {
+			return 0
+		}
+		return d
+	})
+}
+
+func ParetoLatency(scale time.Duration, shape float64) LatencyProfile {
+	return LatencyFunc(func(rng *rand.Rand) time.Duration {
+		u := 1 - rng.Float64()
+		return time.Duration(float64(scale) / math.Pow(u, 1/shape))
+	})
+}
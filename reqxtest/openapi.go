@@ -0,0 +1,254 @@
+package reqxtest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const maxSchemaDepth = 8
+
+var ErrUnsupportedSpec = errors.New("reqxtest.unsupported_spec")
+
+type openAPISpec struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIResponse struct {
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema   *openAPISchema            `json:"schema"`
+	Example  json.RawMessage           `json:"example"`
+	Examples map[string]openAPIExample `json:"examples"`
+}
+
+type openAPIExample struct {
+	Value json.RawMessage `json:"value"`
+}
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Type       any                       `json:"type"`
+	Format     string                    `json:"format"`
+	Example    json.RawMessage           `json:"example"`
+	Default    json.RawMessage           `json:"default"`
+	Enum       []json.RawMessage         `json:"enum"`
+	Minimum    *float64                  `json:"minimum"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	Items      *openAPISchema            `json:"items"`
+	AllOf      []*openAPISchema          `json:"allOf"`
+	OneOf      []*openAPISchema          `json:"oneOf"`
+	AnyOf      []*openAPISchema          `json:"anyOf"`
+}
+
+var stubMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// OpenAPI registers a stub for every operation in an OpenAPI 3 document in
+// JSON form. Stubs registered afterwards take precedence.
+func (s *Server) OpenAPI(spec []byte) error {
+	var doc openAPISpec
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return ErrUnsupportedSpec
+	}
+
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range sortedKeys(doc.Paths[path]) {
+			if !slices.Contains(stubMethods, method) {
+				continue
+			}
+
+			status, response := successResponse(doc.Paths[path][method].Responses)
+			stub := s.Stub(strings.ToUpper(method), path).Respond(status, nil)
+			for _, contentType := range sortedKeys(response.Content) {
+				if !strings.Contains(contentType, "json") {
+					continue
+				}
+
+				body, err := json.Marshal(doc.example(response.Content[contentType]))
+				if err != nil {
+					return err
+				}
+				stub.Header("Content-Type", contentType)
+				stub.Respond(status, body)
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+func successResponse(responses map[string]openAPIResponse) (int, openAPIResponse) {
+	for _, code := range sortedKeys(responses) {
+		status, err := strconv.Atoi(code)
+		if err == nil && status >= 200 && status < 300 {
+			return status, responses[code]
+		}
+	}
+	if response, ok := responses["default"]; ok {
+		return http.StatusOK, response
+	}
+
+	return http.StatusOK, openAPIResponse{}
+}
+
+func (doc *openAPISpec) example(media openAPIMediaType) any {
+	if value, ok := decodeRaw(media.Example); ok {
+		return value
+	}
+	for _, name := range sortedKeys(media.Examples) {
+		if value, ok := decodeRaw(media.Examples[name].Value); ok {
+			return value
+		}
+	}
+
+	return doc.generate(media.Schema, 0)
+}
+
+// Recursive references stop at the first repetition, so a self-referencing
+// schema produces null rather than an arbitrarily deep document.
+func (doc *openAPISpec) generate(schema *openAPISchema, depth int, refs ...string) any {
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		if slices.Contains(refs, schema.Ref) {
+			return nil
+		}
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		return doc.generate(doc.Components.Schemas[name], depth+1, append(refs, schema.Ref)...)
+	}
+
+	for _, raw := range []json.RawMessage{schema.Example, schema.Default} {
+		if value, ok := decodeRaw(raw); ok {
+			return value
+		}
+	}
+	if len(schema.Enum) > 0 {
+		if value, ok := decodeRaw(schema.Enum[0]); ok {
+			return value
+		}
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, part := range schema.AllOf {
+			if object, ok := doc.generate(part, depth+1, refs...).(map[string]any); ok {
+				for k, v := range object {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, choices := range [][]*openAPISchema{schema.OneOf, schema.AnyOf} {
+		if len(choices) > 0 {
+			return doc.generate(choices[0], depth+1, refs...)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		object := map[string]any{}
+		for name, property := range schema.Properties {
+			object[name] = doc.generate(property, depth+1, refs...)
+		}
+		return object
+	case "array":
+		return []any{doc.generate(schema.Items, depth+1, refs...)}
+	case "integer":
+		if schema.Minimum != nil {
+			return int64(*schema.Minimum)
+		}
+		return 0
+	case "number":
+		if schema.Minimum != nil {
+			return *schema.Minimum
+		}
+		return 0.0
+	case "boolean":
+		return false
+	case "string":
+		return exampleString(schema.Format)
+	default:
+		return nil
+	}
+}
+
+// OpenAPI 3.1 allows a list of types, e.g. ["string", "null"].
+func schemaType(schema *openAPISchema) string {
+	switch t := schema.Type.(type) {
+	case string:
+		return t
+	case []any:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && name != "null" {
+				return name
+			}
+		}
+	}
+
+	if len(schema.Properties) > 0 {
+		return "object"
+	}
+	return ""
+}
+
+func exampleString(format string) string {
+	switch format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	default:
+		return "string"
+	}
+}
+
+func decodeRaw(raw json.RawMessage) (any, bool) {
+	if len(raw) == 0 {
+		return nil, false
+	}
+
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,195 @@
+package reqxtest
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Server struct {
+	*httptest.Server
+
+	mu    sync.Mutex
+	rng   *rand.Rand
+	stubs []*Stub
+}
+
+type Stub struct {
+	server      *Server
+	method      string
+	path        string
+	status      int
+	body        []byte
+	headers     http.Header
+	latency     LatencyProfile
+	errorRate   float64
+	errorStatus int
+}
+
+func NewServer() *Server {
+	s := &Server{
+		rng: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+func (s *Server) Seed(seed uint64) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rng = rand.New(rand.NewPCG(seed, seed))
+	return s
+}
+
+func (s *Server) Stub(method, path string) *Stub {
+	stub := &Stub{
+		server:  s,
+		method:  method,
+		path:    path,
+		status:  http.StatusOK,
+		headers: make(http.Header),
+	}
+
+	s.mu.Lock()
+	s.stubs = append(s.stubs, stub)
+	s.mu.Unlock()
+
+	return stub
+}
+
+// Stubs may be reconfigured while the server is handling requests, so
+// every setter takes the server's lock.
+func (st *Stub) Respond(status int, body []byte) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+
+	st.status = status
+	st.body = body
+	return st
+}
+
+func (st *Stub) JSON(status int, v any) *Stub {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+
+	st.headers.Set("Content-Type", "application/json")
+	st.status = status
+	st.body = body
+	return st
+}
+
+func (st *Stub) Header(key, value string) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+
+	st.headers.Set(key, value)
+	return st
+}
+
+func (st *Stub) Latency(profile LatencyProfile) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+
+	st.latency = profile
+	return st
+}
+
+func (st *Stub) ErrorRate(rate float64, status int) *Stub {
+	st.server.mu.Lock()
+	defer st.server.mu.Unlock()
+
+	st.errorRate = rate
+	st.errorStatus = status
+	return st
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	stub := s.match(r)
+	if stub == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	var delay time.Duration
+	if stub.latency != nil {
+		delay = stub.latency.Next(s.rng)
+	}
+	failed := stub.errorRate > 0 && s.rng.Float64() < stub.errorRate
+	status, body, headers, errorStatus := stub.status, stub.body, stub.headers.Clone(), stub.errorStatus
+	s.mu.Unlock()
+
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+
+	if failed {
+		if errorStatus <= 0 {
+			panic(http.ErrAbortHandler)
+		}
+		w.WriteHeader(errorStatus)
+		return
+	}
+
+	for k, values := range headers {
+		w.Header()[k] = values
+	}
+	w.WriteHeader(status)
+	_, _ = w.Write(body)
+}
+
+func (s *Server) match(r *http.Request) *Stub {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.stubs) - 1; i >= 0; i-- {
+		stub := s.stubs[i]
+		if (stub.method == "" || stub.method == r.Method) && matchPath(stub.path, r.URL.Path) {
+			return stub
+		}
+	}
+
+	return nil
+}
+
+// Path segments written as {name} match any single segment.
+func matchPath(pattern, path string) bool {
+	if !strings.Contains(pattern, "{") {
+		return pattern == path
+	}
+
+	patternParts := strings.Split(pattern, "/")
+	pathParts := strings.Split(path, "/")
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+
+	for i, part := range patternParts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if pathParts[i] == "" {
+				return false
+			}
+			continue
+		}
+		if part != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,38 @@
+package reqxtest
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestStubReconfiguredWhileServing(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	stub := server.Stub(http.MethodGet, "/flag").Respond(http.StatusOK, []byte("0"))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 100 {
+			stub.Respond(http.StatusOK, []byte(strconv.Itoa(i))).Header("X-Version", strconv.Itoa(i))
+		}
+	}()
+
+	for range 50 {
+		resp, err := http.Get(server.URL + "/flag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("status = %d", resp.StatusCode)
+		}
+	}
+	wg.Wait()
+}
@@ -1,12 +1,54 @@
 package reqx
 
 import (
+	"context"
 	"errors"
 	"net"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
+var idempotentMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodTrace,
+	http.MethodPut,
+	http.MethodDelete,
+}
+
+func (h *ClientBuilder) IdempotentRetries() *ClientBuilder {
+	h.idempotentRetries = true
+	return h
+}
+
+func (r *RequestBuilder) AllowRetry() *RequestBuilder {
+	r.allowRetry = true
+	return r
+}
+
+func (r *RequestBuilder) retryAllowed() bool {
+	if !r.client.idempotentRetries || r.allowRetry || slices.Contains(idempotentMethods, string(r.method)) {
+		return true
+	}
+
+	if r.client.idempotencyHeader != "" {
+		return true
+	}
+
+	for _, headers := range []map[string]string{r.client.headers, r.headers} {
+		for name := range headers {
+			if strings.EqualFold(name, "Idempotency-Key") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (r *RequestBuilder) shouldRetry(err error, statusCode int) bool {
 	if err != nil {
 		var netErr net.Error
@@ -21,44 +63,175 @@ func (r *RequestBuilder) shouldRetry(err error, statusCode int) bool {
 	return statusCode >= http.StatusInternalServerError || statusCode == http.StatusTooManyRequests
 }
 
-func (r *RequestBuilder) executeWithRetry(fn func() (*Response, error)) (*Response, error) {
+func (r *RequestBuilder) executeWithRetry(ctx context.Context, fn func() (*Response, error)) (*Response, error) {
 	maxRetries := r.client.retryConfig.MaxRetries
-	backoffMs := r.client.retryConfig.BackoffMs
+	if !r.client.capabilities.Has(CapRetries) {
+		maxRetries = 0
+	}
 
 	var lastErr error
 	var lastResp *Response
+	var attemptLog []RetryAttempt
+	var backoffDuration time.Duration
+	failover := r.failoverTargets()
 
 	for attempt := 0; attempt <= maxRetries; attempt++ {
 		resp, err := fn()
+		decision, failed := r.decide(resp, err, attempt+1)
 
-		if err == nil && !r.shouldRetry(nil, resp.Status) {
+		if !failed {
 			return resp, nil
 		}
 
 		lastErr = err
 		lastResp = resp
 
+		entry := RetryAttempt{Attempt: attempt + 1, Err: err}
+		if resp != nil {
+			entry.Status = resp.Status
+		}
+		attemptLog = append(attemptLog, entry)
+
+		if decision == ErrorAbort {
+			return lastResp, lastErr
+		}
+
 		if attempt == maxRetries {
 			break
 		}
 
-		shouldRetry := r.shouldRetry(err, 0)
-		if resp != nil {
-			shouldRetry = shouldRetry || r.shouldRetry(nil, resp.Status)
+		if !r.rewindBody() {
+			r.log().Debug("not retrying request with a body that cannot be replayed",
+				"package", "reqx",
+				"method", string(r.method),
+				"path", r.path,
+			)
+			return lastResp, lastErr
 		}
 
-		if !shouldRetry {
-			break
+		if resp != nil && resp.BodyReader != nil {
+			resp.BodyReader.Close()
+		}
+
+		if decision == ErrorFailover && len(failover) > 0 {
+			r.baseUrl, failover = failover[0], failover[1:]
+			backoffDuration = 0
+		} else {
+			backoffDuration = r.client.retryConfig.backoff(attempt, backoffDuration)
+			if retryAfter, ok := r.client.retryConfig.retryAfter(resp); ok {
+				backoffDuration = retryAfter
+			}
+		}
+		attemptLog[len(attemptLog)-1].Backoff = backoffDuration
+
+		r.log().Debug("retrying request",
+			"package", "reqx",
+			"method", string(r.method),
+			"path", r.path,
+			"attempt", attempt+1,
+			"backoff", backoffDuration,
+			"error", err,
+		)
+
+		r.client.hooks.retry(attempt+1, backoffDuration)
+		r.client.hooks.retryEvent(func() RetryEvent {
+			return RetryEvent{
+				Attempt: attempt + 1,
+				Wait:    backoffDuration,
+				Status:  entry.Status,
+				Err:     err,
+				Method:  string(r.method),
+				URL:     r.client.redactURL(r.buildUrl()),
+				Time:    time.Now(),
+			}
+		})
+
+		if err := sleepContext(ctx, backoffDuration); err != nil {
+			return lastResp, canceledCause(ctx, err)
 		}
+	}
+
+	exhausted := &RetryExhaustedError{
+		Attempts: len(attemptLog),
+		Log:      attemptLog,
+		Err:      lastErr,
+	}
+	if lastResp != nil {
+		exhausted.LastStatus = lastResp.Status
+		exhausted.BodyExcerpt = bodyExcerpt(lastResp.Body)
+		if lastErr == nil {
+			exhausted.Err = &HTTPError{Status: lastResp.Status, Body: lastResp.Body}
+		}
+	}
+
+	return lastResp, exhausted
+}
+
+// A ShouldRetry predicate replaces the built-in status and error rules;
+// RetryOn rules still apply on top of it.
+func (r *RequestBuilder) retryable(resp *Response, err error, attempt int) bool {
+	if !r.retryAllowed() {
+		return false
+	}
+
+	if r.matchesRetryRule(resp) {
+		return true
+	}
+
+	if predicate := r.client.retryConfig.ShouldRetry; predicate != nil {
+		return predicate(resp, err, attempt)
+	}
 
-		backoffDuration := time.Duration(backoffMs*(attempt+1)) * time.Millisecond
+	if r.shouldRetry(err, 0) {
+		return true
+	}
+
+	return resp != nil && r.shouldRetry(nil, resp.Status)
+}
 
-		time.Sleep(backoffDuration)
+func (r *RequestBuilder) retryReusedConnection(exec *execution, req *http.Request, err error, trace *timingTrace) bool {
+	if !r.client.capabilities.Has(CapRetries) || exec.reuseRetried || !isStaleConnection(req, err, trace) || !isIdempotent(req, r.client.idempotencyHeader) || !r.rewindBody() {
+		return false
 	}
+	exec.reuseRetried = true
+	exec.freshConn = true
+
+	r.log().Debug("retrying request on a fresh connection",
+		"package", "reqx",
+		"method", string(r.method),
+		"path", r.path,
+		"error", err,
+	)
+
+	return true
+}
+
+// A kept-alive connection the server dropped fails before any byte of a
+// response arrives; timeouts and cancellations are the caller's own doing.
+func isStaleConnection(req *http.Request, err error, trace *timingTrace) bool {
+	return trace.connReused() && !trace.responded() && req.Context().Err() == nil && !IsTimeout(err)
+}
+
+// The retry dials through a copy of the transport without keep-alives, so
+// it cannot pick another stale connection and the pool is left alone.
+func freshConnection(httpClient *http.Client) *http.Client {
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		return httpClient
+	}
+
+	transport = transport.Clone()
+	transport.DisableKeepAlives = true
+
+	fresh := *httpClient
+	fresh.Transport = transport
+	return &fresh
+}
 
-	if lastErr != nil {
-		return lastResp, lastErr
+func isIdempotent(req *http.Request, idempotencyHeader string) bool {
+	if slices.Contains(idempotentMethods, req.Method) {
+		return true
 	}
 
-	return lastResp, ErrMaxRetriesExceeded
+	return idempotencyHeader != "" && req.Header.Get(idempotencyHeader) != ""
 }
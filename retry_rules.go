@@ -0,0 +1,72 @@
+package reqx
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+type RetryRule func(resp *Response) bool
+
+func (h *ClientBuilder) RetryOn(rules ...RetryRule) *ClientBuilder {
+	h.retryRules = append(h.retryRules, rules...)
+	return h
+}
+
+func (c *RequestBuilder) RetryOn(rules ...RetryRule) *RequestBuilder {
+	c.retryRules = append(c.retryRules, rules...)
+	return c
+}
+
+func (h *ClientBuilder) RetryIf(predicate func(resp *Response, err error, attempt int) bool) *ClientBuilder {
+	h.retryConfig.ShouldRetry = predicate
+	return h
+}
+
+func RetryWhenJSON(path string, value any) RetryRule {
+	expected, err := normalizeJSONValue(value)
+
+	return func(resp *Response) bool {
+		if err != nil || len(resp.Body) == 0 {
+			return false
+		}
+
+		raw, ok, lookupErr := lookupJSONPath(resp.Body, path)
+		if lookupErr != nil || !ok {
+			return false
+		}
+
+		var actual any
+		if json.Unmarshal(raw, &actual) != nil {
+			return false
+		}
+
+		return reflect.DeepEqual(actual, expected)
+	}
+}
+
+func normalizeJSONValue(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	err = json.Unmarshal(data, &normalized)
+	return normalized, err
+}
+
+func (r *RequestBuilder) matchesRetryRule(resp *Response) bool {
+	if resp == nil || resp.BodyReader != nil {
+		return false
+	}
+
+	for _, rules := range [][]RetryRule{r.client.retryRules, r.retryRules} {
+		for _, rule := range rules {
+			if rule(resp) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,86 @@
+package reqx
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// dropSecondRequest answers every request except the second, for which it
+// reads the request and closes the kept-alive connection without a reply.
+func dropSecondRequest(t *testing.T) (addr string, conns, requests *atomic.Int32) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	conns, requests = new(atomic.Int32), new(atomic.Int32)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns.Add(1)
+			go func() {
+				defer conn.Close()
+				reader := bufio.NewReader(conn)
+				for {
+					req, err := http.ReadRequest(reader)
+					if err != nil {
+						return
+					}
+					io.Copy(io.Discard, req.Body)
+					if requests.Add(1) == 2 {
+						return
+					}
+					io.WriteString(conn, "HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok")
+				}
+			}()
+		}
+	}()
+
+	return "http://" + listener.Addr().String(), conns, requests
+}
+
+func TestStaleConnectionRetriedOnNewConnection(t *testing.T) {
+	addr, conns, requests := dropSecondRequest(t)
+
+	client := NewClientBuilder().BaseUrl(addr).RetryConfig(0, 0).Build()
+	defer client.Close()
+
+	if _, err := client.Put("/items/1").Body("first").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Put("/items/1").Body("second").DoRaw()
+	if err != nil {
+		t.Fatalf("request on a dropped connection was not retried: %v", err)
+	}
+	if string(resp.Body) != "ok" {
+		t.Errorf("body = %q", resp.Body)
+	}
+	if requests.Load() != 3 || conns.Load() != 2 {
+		t.Errorf("requests = %d, connections = %d; want 3 requests over 2 connections", requests.Load(), conns.Load())
+	}
+}
+
+func TestStaleConnectionNotRetriedForPost(t *testing.T) {
+	addr, _, requests := dropSecondRequest(t)
+
+	client := NewClientBuilder().BaseUrl(addr).RetryConfig(0, 0).Build()
+	defer client.Close()
+
+	if _, err := client.Post("/items").Body("first").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Post("/items").Body("second").DoRaw(); err == nil {
+		t.Fatal("a POST on a dropped connection was retried")
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2", requests.Load())
+	}
+}
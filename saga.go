@@ -0,0 +1,118 @@
+package reqx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type CompensationError struct {
+	Step string
+	Err  error
+}
+
+type SagaError struct {
+	Step          string
+	Err           error
+	Compensated   []string
+	Compensations []CompensationError
+}
+
+func (e *SagaError) Error() string {
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("%s: step %q: %v", ErrSagaFailed.Error(), e.Step, e.Err))
+	for _, failure := range e.Compensations {
+		builder.WriteString(fmt.Sprintf("; compensating %q failed: %v", failure.Step, failure.Err))
+	}
+
+	return builder.String()
+}
+
+func (e *SagaError) Is(target error) bool {
+	return target == ErrSagaFailed
+}
+
+func (e *SagaError) Unwrap() error {
+	return e.Err
+}
+
+type sagaStep struct {
+	name       string
+	compensate func() error
+}
+
+type Saga struct {
+	mu      sync.Mutex
+	steps   []sagaStep
+	aborted bool
+}
+
+func NewSaga() *Saga {
+	return &Saga{}
+}
+
+func (s *Saga) Step(name string, action func() error, compensate func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.aborted {
+		return ErrSagaAborted
+	}
+
+	if err := action(); err != nil {
+		return s.abort(name, err)
+	}
+
+	if compensate != nil {
+		s.steps = append(s.steps, sagaStep{name: name, compensate: compensate})
+	}
+	return nil
+}
+
+// The compensating request is built from the step's response, so it can
+// refer to identifiers the server assigned.
+func (s *Saga) Request(name string, request *RequestBuilder, compensate func(resp *Response) *RequestBuilder) (*Response, error) {
+	var resp *Response
+	action := func() error {
+		var err error
+		resp, err = request.DoRaw()
+		return err
+	}
+
+	var undo func() error
+	if compensate != nil {
+		undo = func() error {
+			_, err := compensate(resp).DoRaw()
+			return err
+		}
+	}
+
+	return resp, s.Step(name, action, undo)
+}
+
+func (s *Saga) Abort(name string, cause error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.aborted {
+		return ErrSagaAborted
+	}
+	return s.abort(name, cause)
+}
+
+func (s *Saga) abort(name string, cause error) error {
+	s.aborted = true
+
+	sagaErr := &SagaError{Step: name, Err: cause}
+	for i := len(s.steps) - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if err := step.compensate(); err != nil {
+			sagaErr.Compensations = append(sagaErr.Compensations, CompensationError{Step: step.name, Err: err})
+			continue
+		}
+		sagaErr.Compensated = append(sagaErr.Compensated, step.name)
+	}
+	s.steps = nil
+
+	return sagaErr
+}
@@ -0,0 +1,104 @@
+package reqx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type ScheduleHandler func(resp *Response, err error)
+
+type scheduledJob struct {
+	next    func(after time.Time) time.Time
+	request *RequestBuilder
+	handler ScheduleHandler
+}
+
+type Scheduler struct {
+	mu   sync.Mutex
+	jobs []*scheduledJob
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Interval jobs run once as soon as the scheduler starts and then every
+// interval after the previous run finished.
+func (s *Scheduler) Every(interval time.Duration, request *RequestBuilder, handler ScheduleHandler) *Scheduler {
+	first := true
+	return s.add(&scheduledJob{
+		next: func(after time.Time) time.Time {
+			if first {
+				first = false
+				return after
+			}
+			return after.Add(interval)
+		},
+		request: request,
+		handler: handler,
+	})
+}
+
+func (s *Scheduler) Cron(expr string, request *RequestBuilder, handler ScheduleHandler) error {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+
+	s.add(&scheduledJob{next: schedule.Next, request: request, handler: handler})
+	return nil
+}
+
+func (s *Scheduler) add(job *scheduledJob) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+	return s
+}
+
+// Run blocks until ctx is done or the clients of all jobs are closed. Runs
+// of the same job never overlap; a run that overshoots its slot delays the
+// next one.
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.mu.Lock()
+	jobs := append([]*scheduledJob(nil), s.jobs...)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			job.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// A job stops with the client its request belongs to.
+func (j *scheduledJob) run(ctx context.Context) {
+	ctx, release := j.request.client.bind(ctx)
+	defer release()
+
+	for {
+		at := j.next(time.Now())
+		if at.IsZero() {
+			return
+		}
+		if err := sleepContext(ctx, time.Until(at)); err != nil {
+			return
+		}
+
+		resp, err := j.request.clone().Context(ctx).DoRaw()
+		if ctx.Err() != nil {
+			return
+		}
+		if j.handler != nil {
+			j.handler(resp, err)
+		}
+	}
+}
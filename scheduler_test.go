@@ -0,0 +1,31 @@
+package reqx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestSchedulerStopsWhenClientCloses(t *testing.T) {
+	server, _ := captureServer(t)
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+
+	ran := make(chan struct{}, 1)
+	scheduler := NewScheduler().Every(time.Hour, client.Get("/poll"), func(resp *Response, err error) {
+		ran <- struct{}{}
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		done <- scheduler.Run(context.Background())
+	}()
+
+	<-ran
+	client.Close()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler kept running after the client closed")
+	}
+}
@@ -0,0 +1,75 @@
+package reqx
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+)
+
+type ShadowConfig struct {
+	BaseUrl     string
+	Percent     float64
+	DiffOptions DiffOptions
+	Compare     func(diff *ResponseDiff, primary *Response, shadow *Response, err error)
+}
+
+func (h *ClientBuilder) Shadow(config ShadowConfig) *ClientBuilder {
+	h.shadow = &config
+	return h
+}
+
+type shadowRequestKey struct{}
+
+func isShadowRequest(req *http.Request) bool {
+	return req.Context().Value(shadowRequestKey{}) != nil
+}
+
+// The shadow reuses the primary's idempotency key, so a shadow environment
+// sharing state with production deduplicates it. It stays out of hooks,
+// metrics, debug logs and HAR recordings.
+func (c *RequestBuilder) mirror(exec *execution, primary *Response) {
+	config := c.client.shadow
+	if config == nil || primary == nil || rand.Float64()*100 >= config.Percent {
+		return
+	}
+	if _, streaming := c.body.(io.Reader); streaming {
+		return
+	}
+	// An absolute URL ignores the shadow base URL and would send the copy
+	// back to the primary.
+	if isAbsoluteUrl(c.client.expandVariables(c.path)) {
+		return
+	}
+
+	ctx, done, err := c.client.begin(nil)
+	if err != nil {
+		return
+	}
+	ctx = context.WithValue(ctx, shadowRequestKey{}, true)
+
+	shadow := c.clone()
+	shadow.baseUrl = config.BaseUrl
+
+	snapshot := *primary
+	snapshot.Headers = primary.Headers.Clone()
+	primary = &snapshot
+
+	spawned := c.client.spawn(func() {
+		defer done()
+
+		resp, err := shadow.attempt(&execution{ctx: ctx, attempt: 1, shadow: true, idempotencyKey: exec.idempotencyKey})
+		if config.Compare == nil {
+			return
+		}
+
+		var diff *ResponseDiff
+		if resp != nil {
+			diff = DiffResponses(primary, resp, config.DiffOptions)
+		}
+		config.Compare(diff, primary, resp, err)
+	})
+	if !spawned {
+		done()
+	}
+}
@@ -0,0 +1,116 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShadowReusesIdempotencyKeyAndSkipsHooks(t *testing.T) {
+	primary, primaryRequest := captureServer(t)
+	shadowKeys := make(chan string, 1)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shadowKeys <- r.Header.Get("Idempotency-Key")
+	}))
+	defer shadow.Close()
+
+	var hooked atomic.Int32
+	compared := make(chan struct{})
+	client := NewClientBuilder().
+		BaseUrl(primary.URL).
+		IdempotencyKeyHeader("Idempotency-Key").
+		OnRequest(func(req *http.Request) { hooked.Add(1) }).
+		OnResponse(func(resp *Response) { hooked.Add(1) }).
+		Shadow(ShadowConfig{
+			BaseUrl: shadow.URL,
+			Percent: 100,
+			Compare: func(diff *ResponseDiff, primary *Response, shadow *Response, err error) {
+				close(compared)
+			},
+		}).
+		Build()
+
+	defer client.Close()
+
+	if _, err := client.Post("/orders").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-compared:
+	case <-time.After(time.Second):
+		t.Fatal("shadow request did not finish")
+	}
+	if got, want := <-shadowKeys, primaryRequest.header.Get("Idempotency-Key"); got == "" || got != want {
+		t.Errorf("shadow idempotency key = %q, want %q", got, want)
+	}
+	if got := hooked.Load(); got != 2 {
+		t.Errorf("hooks ran %d times, want 2 for the primary request only", got)
+	}
+}
+
+func TestCloseCancelsShadowRequests(t *testing.T) {
+	primary, _ := captureServer(t)
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer shadow.Close()
+
+	var shadowErr atomic.Value
+	client := NewClientBuilder().
+		BaseUrl(primary.URL).
+		Shadow(ShadowConfig{
+			BaseUrl: shadow.URL,
+			Percent: 100,
+			Compare: func(diff *ResponseDiff, primary *Response, shadow *Response, err error) {
+				shadowErr.Store(err)
+			},
+		}).
+		Build()
+
+	if _, err := client.Get("/").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+
+	if err, _ := shadowErr.Load().(error); err == nil {
+		t.Error("shadow request was not canceled and awaited by Close")
+	}
+}
+
+func TestShadowSkipsAbsoluteUrls(t *testing.T) {
+	var primaryHits, shadowHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		primaryHits.Add(1)
+	}))
+	defer primary.Close()
+	shadow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		shadowHits.Add(1)
+	}))
+	defer shadow.Close()
+
+	mirrored := make(chan struct{}, 1)
+	client := NewClientBuilder().
+		Shadow(ShadowConfig{
+			BaseUrl: shadow.URL,
+			Percent: 100,
+			Compare: func(diff *ResponseDiff, primary *Response, shadow *Response, err error) {
+				mirrored <- struct{}{}
+			},
+		}).
+		Build()
+	defer client.Close()
+
+	if _, err := client.Post(primary.URL + "/orders").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-mirrored:
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	if primaryHits.Load() != 1 || shadowHits.Load() != 0 {
+		t.Errorf("primary hits = %d, shadow hits = %d; want 1 and 0", primaryHits.Load(), shadowHits.Load())
+	}
+}
@@ -0,0 +1,128 @@
+package reqx
+
+import (
+	"context"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const srvSchemePrefix = "srv+"
+
+type srvEntry struct {
+	records []*net.SRV
+	expires time.Time
+}
+
+type srvResolver struct {
+	ttl    time.Duration
+	lookup func(ctx context.Context, name string) ([]*net.SRV, error)
+
+	mu      sync.Mutex
+	entries map[string]*srvEntry
+}
+
+func newSRVResolver(ttl time.Duration) *srvResolver {
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	return &srvResolver{
+		ttl: ttl,
+		lookup: func(ctx context.Context, name string) ([]*net.SRV, error) {
+			_, records, err := net.DefaultResolver.LookupSRV(ctx, "", "", name)
+			return records, err
+		},
+		entries: make(map[string]*srvEntry),
+	}
+}
+
+func (h *ClientBuilder) SRVCacheTTL(ttl time.Duration) *ClientBuilder {
+	h.srvTTL = ttl
+	return h
+}
+
+func (r *srvResolver) rewrite(ctx context.Context, rawURL string) (string, error) {
+	if !strings.HasPrefix(rawURL, srvSchemePrefix) {
+		return rawURL, nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	records, err := r.resolve(ctx, u.Hostname())
+	if err != nil {
+		return "", err
+	}
+
+	target := pickSRV(records)
+	u.Scheme = strings.TrimPrefix(u.Scheme, srvSchemePrefix)
+	u.Host = net.JoinHostPort(strings.TrimSuffix(target.Target, "."), strconv.Itoa(int(target.Port)))
+
+	return u.String(), nil
+}
+
+func (r *srvResolver) resolve(ctx context.Context, name string) ([]*net.SRV, error) {
+	r.mu.Lock()
+	entry, ok := r.entries[name]
+	r.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.records, nil
+	}
+
+	records, err := r.lookup(ctx, name)
+	if err != nil {
+		if ok {
+			// Keep serving the last known records while DNS is unavailable.
+			return entry.records, nil
+		}
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, &net.DNSError{Err: "no SRV records", Name: name, IsNotFound: true}
+	}
+
+	r.mu.Lock()
+	r.entries[name] = &srvEntry{records: records, expires: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return records, nil
+}
+
+// pickSRV selects a record from the lowest priority group, weighted as described in RFC 2782.
+func pickSRV(records []*net.SRV) *net.SRV {
+	lowest := slices.MinFunc(records, func(a, b *net.SRV) int {
+		return int(a.Priority) - int(b.Priority)
+	}).Priority
+
+	var group []*net.SRV
+	total := 0
+	for _, record := range records {
+		if record.Priority == lowest {
+			group = append(group, record)
+			total += int(record.Weight)
+		}
+	}
+
+	if total == 0 {
+		return group[rand.IntN(len(group))]
+	}
+
+	n := rand.IntN(total)
+	for _, record := range group {
+		n -= int(record.Weight)
+		if n < 0 {
+			return record
+		}
+	}
+
+	return group[len(group)-1]
+}
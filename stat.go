@@ -0,0 +1,55 @@
+package reqx
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type ResourceInfo struct {
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+	AcceptRanges bool
+}
+
+func (c *Client) Stat(path string) (*ResourceInfo, error) {
+	return c.StatContext(context.Background(), path)
+}
+
+// Servers that reject HEAD are probed with a one-byte ranged GET, whose
+// Content-Range still carries the full size.
+func (c *Client) StatContext(ctx context.Context, path string) (*ResourceInfo, error) {
+	resp, err := c.NewRequestBuilder().Method(MethodHead).Path(path).Context(ctx).DoRaw()
+	if resp != nil && (resp.Status == http.StatusMethodNotAllowed || resp.Status == http.StatusNotImplemented) {
+		resp, err = c.Get(path).Context(ctx).Range(0, 0).DoStream()
+		if err == nil {
+			resp.BodyReader.Close()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ResourceInfo{
+		Size:         -1,
+		ContentType:  resp.Headers.Get("Content-Type"),
+		ETag:         resp.Headers.Get("ETag"),
+		AcceptRanges: resp.Headers.Get("Accept-Ranges") == "bytes" || resp.Status == http.StatusPartialContent,
+	}
+	if lastModified, err := http.ParseTime(resp.Headers.Get("Last-Modified")); err == nil {
+		info.LastModified = lastModified
+	}
+
+	if resp.Status == http.StatusPartialContent {
+		if contentRange, err := ParseContentRange(resp.Headers.Get("Content-Range")); err == nil {
+			info.Size = contentRange.Size
+		}
+	} else if size, err := strconv.ParseInt(resp.Headers.Get("Content-Length"), 10, 64); err == nil {
+		info.Size = size
+	}
+
+	return info, nil
+}
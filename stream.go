@@ -0,0 +1,264 @@
+package reqx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrStopStream = errors.New("reqx.stop_stream")
+
+type SSEEvent struct {
+	ID    string
+	Event string
+	Data  string
+	Retry time.Duration
+}
+
+type streamReconnect struct {
+	maxReconnects int
+	backoff       time.Duration
+	cursorParam   string
+	cursor        func(line json.RawMessage) string
+}
+
+func (c *RequestBuilder) StreamReconnect(maxReconnects int, backoff time.Duration) *RequestBuilder {
+	c.reconnect.maxReconnects = maxReconnects
+	c.reconnect.backoff = backoff
+	return c
+}
+
+func (c *RequestBuilder) StreamCursor(param string, cursor func(line json.RawMessage) string) *RequestBuilder {
+	c.reconnect.cursorParam = param
+	c.reconnect.cursor = cursor
+	return c
+}
+
+func (c *RequestBuilder) DoSSE(handler func(event SSEEvent) error) error {
+	lastEventID := ""
+	backoff := c.reconnect.backoff
+
+	return c.consumeStream("text/event-stream", func(rb *RequestBuilder) {
+		if lastEventID != "" {
+			rb.headers["Last-Event-ID"] = lastEventID
+		}
+	}, func() time.Duration {
+		return backoff
+	}, func(body io.Reader, progress func()) (bool, error) {
+		err := readSSE(body, func(event SSEEvent) error {
+			if event.Retry > 0 {
+				backoff = event.Retry
+			}
+			if event.ID != "" {
+				lastEventID = event.ID
+			}
+			if event.Data == "" && event.Event == "" {
+				return nil
+			}
+			progress()
+			return handler(event)
+		})
+		return true, err
+	})
+}
+
+func (c *RequestBuilder) DoNDJSON(handler func(line json.RawMessage) error) error {
+	var lastLine json.RawMessage
+
+	return c.consumeStream("application/x-ndjson", func(rb *RequestBuilder) {
+		if lastLine != nil && c.reconnect.cursor != nil {
+			rb.queryParams[c.reconnect.cursorParam] = c.reconnect.cursor(lastLine)
+		}
+	}, func() time.Duration {
+		return c.reconnect.backoff
+	}, func(body io.Reader, progress func()) (bool, error) {
+		reader := bufio.NewReader(body)
+		for {
+			line, err := reader.ReadBytes('\n')
+			line = bytes.TrimSpace(line)
+			if len(line) > 0 {
+				progress()
+				if handlerErr := handler(json.RawMessage(line)); handlerErr != nil {
+					return false, handlerErr
+				}
+				lastLine = append(json.RawMessage(nil), line...)
+			}
+
+			if errors.Is(err, io.EOF) {
+				return false, nil
+			}
+			if err != nil {
+				return true, err
+			}
+		}
+	})
+}
+
+// The reconnect budget counts consecutive failures: a connection that
+// delivered at least one event resets it, so long-lived streams survive any
+// number of occasional drops.
+func (c *RequestBuilder) consumeStream(
+	accept string,
+	prepare func(rb *RequestBuilder),
+	backoff func() time.Duration,
+	consume func(body io.Reader, progress func()) (bool, error),
+) error {
+	ctx := c.context
+	if ctx == nil {
+		ctx = c.client.context
+	}
+
+	var delay time.Duration
+	for failures := 0; ; failures++ {
+		rb := c.clone()
+		if _, ok := rb.headers["Accept"]; !ok {
+			rb.headers["Accept"] = accept
+		}
+		prepare(rb)
+
+		progressed := false
+		reconnect, err := rb.consumeOnce(func(body io.Reader) (bool, error) {
+			return consume(body, func() { progressed = true })
+		})
+		if err == nil && !reconnect {
+			return nil
+		}
+		if errors.Is(err, ErrStopStream) {
+			return nil
+		}
+		if err != nil && !reconnect {
+			return err
+		}
+		if progressed {
+			failures, delay = 0, 0
+		}
+
+		if failures >= c.reconnect.maxReconnects {
+			if err == nil {
+				return nil
+			}
+			return &RetryExhaustedError{Attempts: failures + 1, Err: err}
+		}
+
+		delay = c.reconnectDelay(backoff(), failures, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Reconnects back off like retries, using the client's strategy, cap and
+// jitter on top of the stream's own base delay.
+func (c *RequestBuilder) reconnectDelay(base time.Duration, failures int, previous time.Duration) time.Duration {
+	config := RetryConfig{BackoffMs: int(base.Milliseconds())}
+	if c.client.retryConfig != nil {
+		config.Strategy = c.client.retryConfig.Strategy
+		config.MaxBackoffMs = c.client.retryConfig.MaxBackoffMs
+		config.Jitter = c.client.retryConfig.Jitter
+	}
+
+	return config.backoff(failures, previous)
+}
+
+func (c *RequestBuilder) consumeOnce(consume func(body io.Reader) (bool, error)) (bool, error) {
+	resp, err := c.DoStream()
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return c.shouldRetry(nil, httpErr.Status), err
+	}
+	if err != nil {
+		return c.shouldRetry(err, 0), err
+	}
+	defer func() {
+		err := resp.BodyReader.Close()
+		if err != nil {
+			c.log().Error("Failed to close stream body",
+				"package", "reqx",
+				"error", err)
+		}
+	}()
+
+	if resp.Status == http.StatusNoContent {
+		return false, nil
+	}
+	return consume(resp.BodyReader)
+}
+
+func readSSE(body io.Reader, dispatch func(event SSEEvent) error) error {
+	reader := bufio.NewReader(body)
+
+	var event SSEEvent
+	var data strings.Builder
+	hasData := false
+
+	for {
+		line, err := reader.ReadString('\n')
+		if errors.Is(err, io.EOF) && line == "" {
+			return nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if hasData || event.Event != "" || event.Retry > 0 || event.ID != "" {
+				event.Data = strings.TrimSuffix(data.String(), "\n")
+				if dispatchErr := dispatch(event); dispatchErr != nil {
+					return dispatchErr
+				}
+			}
+			event = SSEEvent{}
+			data.Reset()
+			hasData = false
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			data.WriteString(value)
+			data.WriteString("\n")
+			hasData = true
+		case "event":
+			event.Event = value
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				event.ID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package reqx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestStreamReconnectBudgetResetsAfterEvents(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := connections.Add(1)
+		if n > 5 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprintf(w, "id: %d\ndata: event %d\n\n", n, n)
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+	defer client.Close()
+
+	var events []string
+	err := client.Get("/events").
+		StreamReconnect(1, time.Millisecond).
+		DoSSE(func(event SSEEvent) error {
+			events = append(events, event.Data)
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 5 {
+		t.Errorf("received %d events, want 5 across dropped connections", len(events))
+	}
+}
+
+func TestStreamReconnectDelayUsesRetryBackoff(t *testing.T) {
+	client := NewClientBuilder().RetryBackoff(BackoffExponential, 50, JitterNone).Build()
+	defer client.Close()
+
+	rb := client.Get("/events")
+	for failures, want := range []time.Duration{10, 20, 40, 50} {
+		if got := rb.reconnectDelay(10*time.Millisecond, failures, 0); got != want*time.Millisecond {
+			t.Errorf("delay after %d failures = %s, want %s", failures, got, want*time.Millisecond)
+		}
+	}
+}
+
+func TestStreamReconnectsAfterExhaustedRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := requests.Add(1); {
+		case n <= 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case n == 3:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: recovered\n\n")
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientBuilder().BaseUrl(server.URL).RetryConfig(1, 1).Build()
+	defer client.Close()
+
+	var events []string
+	err := client.Get("/events").
+		StreamReconnect(2, time.Millisecond).
+		DoSSE(func(event SSEEvent) error {
+			events = append(events, event.Data)
+			return nil
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0] != "recovered" {
+		t.Errorf("events = %q, want the stream to reconnect after a 503", events)
+	}
+}
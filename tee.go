@@ -0,0 +1,33 @@
+package reqx
+
+import (
+	"io"
+)
+
+type teeBody struct {
+	io.ReadCloser
+	reader io.Reader
+}
+
+func (t *teeBody) Read(p []byte) (int, error) {
+	return t.reader.Read(p)
+}
+
+func (c *RequestBuilder) TeeBody(w io.Writer) *RequestBuilder {
+	c.tee = w
+	return c
+}
+
+func (c *RequestBuilder) teeResponse(response *Response) error {
+	if c.tee == nil || response == nil {
+		return nil
+	}
+
+	if response.BodyReader != nil {
+		response.BodyReader = &teeBody{ReadCloser: response.BodyReader, reader: io.TeeReader(response.BodyReader, c.tee)}
+		return nil
+	}
+
+	_, err := c.tee.Write(response.Body)
+	return err
+}
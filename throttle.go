@@ -0,0 +1,100 @@
+package reqx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type AdaptiveThrottleConfig struct {
+	InitialRPS     float64
+	MinRPS         float64
+	MaxRPS         float64
+	Increase       float64
+	DecreaseFactor float64
+}
+
+type adaptiveThrottle struct {
+	config AdaptiveThrottleConfig
+
+	mu    sync.Mutex
+	hosts map[string]*hostThrottle
+}
+
+type hostThrottle struct {
+	bucket       *tokenBucket
+	lastDecrease time.Time
+}
+
+func (h *ClientBuilder) AdaptiveThrottle(config AdaptiveThrottleConfig) *ClientBuilder {
+	if config.MinRPS <= 0 {
+		config.MinRPS = 1
+	}
+	if config.MaxRPS < config.MinRPS {
+		config.MaxRPS = config.MinRPS
+	}
+	if config.InitialRPS <= 0 {
+		config.InitialRPS = config.MaxRPS
+	}
+	if config.Increase <= 0 {
+		config.Increase = 1
+	}
+	if config.DecreaseFactor <= 0 || config.DecreaseFactor >= 1 {
+		config.DecreaseFactor = 0.5
+	}
+
+	h.throttle = &adaptiveThrottle{
+		config: config,
+		hosts:  make(map[string]*hostThrottle),
+	}
+	return h
+}
+
+func (t *adaptiveThrottle) host(name string) *hostThrottle {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	host, ok := t.hosts[name]
+	if !ok {
+		host = &hostThrottle{
+			bucket: newTokenBucket(t.config.InitialRPS, 1),
+		}
+		t.hosts[name] = host
+	}
+
+	return host
+}
+
+func (t *adaptiveThrottle) wait(ctx context.Context, host string) error {
+	return t.host(host).bucket.Wait(ctx)
+}
+
+func (t *adaptiveThrottle) observe(host string, status int) {
+	throttle := t.host(host)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rate := throttle.bucket.currentRate()
+	if status == http.StatusTooManyRequests {
+		if time.Since(throttle.lastDecrease) < time.Second {
+			return
+		}
+		throttle.lastDecrease = time.Now()
+		throttle.bucket.setRate(max(t.config.MinRPS, rate*t.config.DecreaseFactor))
+		return
+	}
+
+	if status < http.StatusInternalServerError {
+		throttle.bucket.setRate(min(t.config.MaxRPS, rate+t.config.Increase))
+	}
+}
+
+func (c *Client) ThrottleRate(host string) float64 {
+	if c.throttle == nil {
+		return 0
+	}
+
+	return c.throttle.host(host).bucket.currentRate()
+}
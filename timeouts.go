@@ -0,0 +1,123 @@
+package reqx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type TimeoutConfig struct {
+	Connect        time.Duration
+	TLSHandshake   time.Duration
+	ResponseHeader time.Duration
+	BodyRead       time.Duration
+	Total          time.Duration
+}
+
+func (h *ClientBuilder) Timeouts(config TimeoutConfig) *ClientBuilder {
+	if config.Connect > 0 {
+		h.ConnectTimeout(config.Connect)
+	}
+	if config.TLSHandshake > 0 {
+		h.TLSHandshakeTimeout(config.TLSHandshake)
+	}
+	if config.ResponseHeader > 0 {
+		h.ResponseHeaderTimeout(config.ResponseHeader)
+	}
+	if config.BodyRead > 0 {
+		h.BodyReadTimeout(config.BodyRead)
+	}
+	if config.Total > 0 {
+		h.Timeout(config.Total)
+	}
+	return h
+}
+
+func (h *ClientBuilder) ConnectTimeout(timeout time.Duration) *ClientBuilder {
+	h.connectTimeout = timeout
+	return h
+}
+
+func (h *ClientBuilder) TLSHandshakeTimeout(timeout time.Duration) *ClientBuilder {
+	h.transport.TLSHandshakeTimeout = timeout
+	return h
+}
+
+func (h *ClientBuilder) ResponseHeaderTimeout(timeout time.Duration) *ClientBuilder {
+	h.transport.ResponseHeaderTimeout = timeout
+	return h
+}
+
+func (h *ClientBuilder) BodyReadTimeout(timeout time.Duration) *ClientBuilder {
+	h.bodyReadTimeout = timeout
+	return h
+}
+
+func connectTimeoutDialer(timeout time.Duration, next dialFunc) dialFunc {
+	if next == nil {
+		next = (&net.Dialer{}).DialContext
+	}
+
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return next(ctx, network, address)
+	}
+}
+
+// The body timer starts once response headers arrive, so it bounds the
+// download independently of how long the server took to answer.
+func (c *Client) limitBodyRead(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		ctx, cancel := context.WithCancelCause(req.Context())
+
+		resp, err := next(req.WithContext(ctx))
+		if err != nil {
+			cancel(nil)
+			return resp, err
+		}
+
+		body := &timedBody{ReadCloser: resp.Body, cancel: cancel}
+		body.timer = time.AfterFunc(c.bodyReadTimeout, func() {
+			body.mu.Lock()
+			body.expired = true
+			body.mu.Unlock()
+			cancel(ErrBodyTimeout)
+		})
+		resp.Body = body
+		return resp, nil
+	}
+}
+
+type timedBody struct {
+	io.ReadCloser
+	timer   *time.Timer
+	cancel  context.CancelCauseFunc
+	mu      sync.Mutex
+	expired bool
+}
+
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		b.mu.Lock()
+		expired := b.expired
+		b.mu.Unlock()
+		if expired {
+			return n, fmt.Errorf("%w: %w", ErrBodyTimeout, context.DeadlineExceeded)
+		}
+	}
+	return n, err
+}
+
+func (b *timedBody) Close() error {
+	b.timer.Stop()
+	err := b.ReadCloser.Close()
+	b.cancel(nil)
+	return err
+}
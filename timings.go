@@ -0,0 +1,93 @@
+package reqx
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings breaks down the latency of the attempt that produced a response.
+// Phases skipped on a reused connection stay zero.
+type Timings struct {
+	DNS          time.Duration
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	TTFB         time.Duration
+	Total        time.Duration
+	ConnReused   bool
+}
+
+// Dials may finish on another goroutine after the request took a different
+// connection, so the timestamps are guarded.
+type timingTrace struct {
+	mu        sync.Mutex
+	start     time.Time
+	dnsStart  time.Time
+	dnsDone   time.Time
+	dialStart time.Time
+	dialDone  time.Time
+	tlsStart  time.Time
+	tlsDone   time.Time
+	firstByte time.Time
+	reused    bool
+}
+
+func (t *timingTrace) mark(at *time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if at.IsZero() {
+		*at = time.Now()
+	}
+}
+
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart:      func(string, string) { t.mark(&t.dialStart) },
+		ConnectDone:       func(string, string, error) { t.mark(&t.dialDone) },
+		TLSHandshakeStart: func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mark(&t.tlsDone)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			t.reused = info.Reused
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+func (t *timingTrace) connReused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reused
+}
+
+func (t *timingTrace) responded() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.firstByte.IsZero()
+}
+
+func (t *timingTrace) timings(end time.Time) Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Timings{
+		DNS:          span(t.dnsStart, t.dnsDone),
+		Connect:      span(t.dialStart, t.dialDone),
+		TLSHandshake: span(t.tlsStart, t.tlsDone),
+		TTFB:         span(t.start, t.firstByte),
+		Total:        end.Sub(t.start),
+		ConnReused:   t.reused,
+	}
+}
+
+func span(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,255 @@
+package reqx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+type Token struct {
+	Value  string    `json:"value"`
+	Expiry time.Time `json:"expiry,omitempty"`
+}
+
+type ExpiringTokenSource interface {
+	TokenSource
+	ExpiringToken(ctx context.Context) (*Token, error)
+}
+
+type RefreshableTokenSource interface {
+	TokenSource
+	Refresh(ctx context.Context) error
+}
+
+type TokenStore interface {
+	Load(ctx context.Context, key string) (*Token, error)
+	Save(ctx context.Context, key string, token *Token) error
+}
+
+type StaticTokenSource string
+
+func (s StaticTokenSource) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+type cachedTokenSource struct {
+	source TokenSource
+	store  TokenStore
+	key    string
+	ttl    time.Duration
+
+	mu     sync.Mutex
+	cached *Token
+}
+
+func CachedTokenSource(source TokenSource, store TokenStore, key string, ttl time.Duration) TokenSource {
+	return &cachedTokenSource{
+		source: source,
+		store:  store,
+		key:    key,
+		ttl:    ttl,
+	}
+}
+
+// The store is only consulted when the in-memory token has expired, and an
+// unreachable store counts as a miss, so a store outage never fails a request
+// the source itself could serve.
+func (s *cachedTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached.valid() {
+		return s.cached.Value, nil
+	}
+
+	stored, err := s.store.Load(ctx, s.key)
+	if err != nil {
+		loggerFrom(ctx).Warn("failed to load cached token",
+			"package", "reqx",
+			"key", s.key,
+			"error", err)
+	}
+	if stored.valid() {
+		s.cached = stored
+		return stored.Value, nil
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	s.cached = token
+	s.save(ctx, token)
+
+	return token.Value, nil
+}
+
+func (s *cachedTokenSource) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if source, ok := s.source.(RefreshableTokenSource); ok {
+		if err := source.Refresh(ctx); err != nil {
+			return err
+		}
+	}
+
+	token, err := s.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	s.cached = token
+	s.save(ctx, token)
+
+	return nil
+}
+
+// Persisting is best effort: a store outage must not break requests that
+// already hold a valid token.
+func (s *cachedTokenSource) save(ctx context.Context, token *Token) {
+	if err := s.store.Save(ctx, s.key, token); err != nil {
+		loggerFrom(ctx).Warn("failed to save cached token",
+			"package", "reqx",
+			"key", s.key,
+			"error", err)
+	}
+}
+
+func (s *cachedTokenSource) fetch(ctx context.Context) (*Token, error) {
+	if source, ok := s.source.(ExpiringTokenSource); ok {
+		return source.ExpiringToken(ctx)
+	}
+
+	value, err := s.source.Token(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &Token{Value: value}
+	if s.ttl > 0 {
+		token.Expiry = time.Now().Add(s.ttl)
+	}
+
+	return token, nil
+}
+
+func (t *Token) valid() bool {
+	if t == nil || t.Value == "" {
+		return false
+	}
+
+	return t.Expiry.IsZero() || time.Until(t.Expiry) > tokenExpiryLeeway
+}
+
+type memoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]Token
+}
+
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[string]Token)}
+}
+
+func (s *memoryTokenStore) Load(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+func (s *memoryTokenStore) Save(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[key] = *token
+	return nil
+}
+
+type fileTokenStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileTokenStore(path string) TokenStore {
+	return &fileTokenStore{path: path}
+}
+
+func (s *fileTokenStore) Load(ctx context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokens[key]
+	if !ok {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+func (s *fileTokenStore) Save(ctx context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = *token
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *fileTokenStore) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,57 @@
+package reqx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+type flakyTokenStore struct {
+	loads atomic.Int32
+}
+
+func (s *flakyTokenStore) Load(ctx context.Context, key string) (*Token, error) {
+	s.loads.Add(1)
+	return nil, errors.New("store unavailable")
+}
+
+func (s *flakyTokenStore) Save(ctx context.Context, key string, token *Token) error {
+	return errors.New("store unavailable")
+}
+
+func TestCachedTokenSourceSurvivesStoreOutage(t *testing.T) {
+	server, captured := captureServer(t)
+	store := &flakyTokenStore{}
+	source := &countingTokenSource{}
+
+	var logs bytes.Buffer
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		TokenSource(CachedTokenSource(source, store, "api", 0)).
+		Logger(slog.New(slog.NewTextHandler(&logs, nil))).
+		Build()
+	defer client.Close()
+
+	for range 3 {
+		if _, err := client.Get("/").DoRaw(); err != nil {
+			t.Fatalf("request failed during a store outage: %v", err)
+		}
+	}
+
+	if got := captured.header.Get("Authorization"); got != "Bearer token-1" {
+		t.Errorf("Authorization = %q", got)
+	}
+	if got := store.loads.Load(); got != 1 {
+		t.Errorf("store loaded %d times, want 1", got)
+	}
+	if got := source.calls.Load(); got != 1 {
+		t.Errorf("source called %d times, want 1", got)
+	}
+	if !strings.Contains(logs.String(), "failed to load cached token") {
+		t.Errorf("store error was not logged: %s", logs.String())
+	}
+}
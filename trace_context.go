@@ -0,0 +1,157 @@
+package reqx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type TraceFormat int
+
+const (
+	TraceW3C TraceFormat = iota
+	TraceB3
+	TraceB3Single
+)
+
+type SpanContext struct {
+	TraceID    string
+	SpanID     string
+	Sampled    bool
+	TraceState string
+}
+
+func (s SpanContext) IsValid() bool {
+	return isTraceHex(s.TraceID, 32) && isTraceHex(s.SpanID, 16)
+}
+
+type TraceSource func(ctx context.Context) (SpanContext, bool)
+
+type spanContextKey struct{}
+
+func ContextWithSpan(ctx context.Context, span SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+func SpanFromContext(ctx context.Context) (SpanContext, bool) {
+	span, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return span, ok && span.IsValid()
+}
+
+func ParseTraceparent(traceparent, tracestate string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || !isTraceHex(parts[0], 2) || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) || !isTraceHex(parts[3], 2) {
+		return SpanContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceparent, traceparent)
+	}
+
+	flags, _ := hex.DecodeString(parts[3])
+	span := SpanContext{
+		TraceID:    parts[1],
+		SpanID:     parts[2],
+		Sampled:    flags[0]&1 == 1,
+		TraceState: tracestate,
+	}
+	if !span.IsValid() {
+		return SpanContext{}, fmt.Errorf("%w: %q", ErrInvalidTraceparent, traceparent)
+	}
+
+	return span, nil
+}
+
+func (h *ClientBuilder) PropagateTrace(formats ...TraceFormat) *ClientBuilder {
+	if len(formats) == 0 {
+		formats = []TraceFormat{TraceW3C}
+	}
+
+	h.traceFormats = formats
+	return h
+}
+
+func (h *ClientBuilder) TraceSource(source TraceSource) *ClientBuilder {
+	h.traceSource = source
+	return h
+}
+
+// Every attempt is sent as a new child span of the current one. Without a
+// span in the context, a traceparent received by InboundHeadersMiddleware
+// is continued.
+func (c *RequestBuilder) propagateTrace(ctx context.Context, req *http.Request) {
+	if len(c.client.traceFormats) == 0 {
+		return
+	}
+
+	parent, ok := c.client.spanContext(ctx)
+	if !ok {
+		return
+	}
+
+	spanID := make([]byte, 8)
+	if _, err := rand.Read(spanID); err != nil {
+		return
+	}
+	child := parent
+	child.SpanID = hex.EncodeToString(spanID)
+
+	sampled := "0"
+	if child.Sampled {
+		sampled = "1"
+	}
+
+	for _, format := range c.client.traceFormats {
+		switch format {
+		case TraceW3C:
+			setIfAbsent(req.Header, "Traceparent", fmt.Sprintf("00-%s-%s-0%s", child.TraceID, child.SpanID, sampled))
+			if child.TraceState != "" {
+				setIfAbsent(req.Header, "Tracestate", child.TraceState)
+			}
+		case TraceB3:
+			if req.Header.Get("X-B3-TraceId") == "" {
+				req.Header.Set("X-B3-TraceId", child.TraceID)
+				req.Header.Set("X-B3-SpanId", child.SpanID)
+				req.Header.Set("X-B3-ParentSpanId", parent.SpanID)
+				req.Header.Set("X-B3-Sampled", sampled)
+			}
+		case TraceB3Single:
+			setIfAbsent(req.Header, "B3", fmt.Sprintf("%s-%s-%s-%s", child.TraceID, child.SpanID, sampled, parent.SpanID))
+		}
+	}
+}
+
+func (c *Client) spanContext(ctx context.Context) (SpanContext, bool) {
+	if c.traceSource != nil {
+		if span, ok := c.traceSource(ctx); ok && span.IsValid() {
+			return span, true
+		}
+	}
+	if span, ok := SpanFromContext(ctx); ok {
+		return span, true
+	}
+
+	inbound, ok := ctx.Value(inboundHeadersKey{}).(http.Header)
+	if !ok || inbound.Get("Traceparent") == "" {
+		return SpanContext{}, false
+	}
+	span, err := ParseTraceparent(inbound.Get("Traceparent"), inbound.Get("Tracestate"))
+	return span, err == nil
+}
+
+func setIfAbsent(header http.Header, name, value string) {
+	if header.Get(name) == "" {
+		header.Set(name, value)
+	}
+}
+
+func isTraceHex(value string, length int) bool {
+	if len(value) != length || strings.Trim(value, "0") == "" && length > 2 {
+		return false
+	}
+	for _, r := range value {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,372 @@
+package reqx
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDumpFileBytes = 64 << 20
+	defaultDumpFiles     = 10
+	defaultDumpBodyBytes = 1 << 20
+	trafficDumpPrefix    = "traffic-"
+	trafficDumpSuffix    = ".jsonl.gz"
+)
+
+var dumpSensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Token requests and responses carry these in form and JSON bodies.
+var defaultRedactedBodyFields = []string{
+	"access_token",
+	"refresh_token",
+	"id_token",
+	"client_secret",
+	"client_assertion",
+	"code_verifier",
+	"password",
+}
+
+type TrafficDumpConfig struct {
+	Dir          string
+	MaxFileBytes int64
+	MaxFiles     int
+	MaxBodyBytes int64
+
+	// Form and JSON fields whose values are replaced in recorded bodies.
+	// Nil selects the OAuth credential fields; an empty slice records
+	// bodies unredacted.
+	RedactBodyFields []string
+}
+
+type TrafficRecord struct {
+	Time      time.Time         `json:"time"`
+	Duration  time.Duration     `json:"duration"`
+	Request   RecordedRequest   `json:"request"`
+	Response  *RecordedResponse `json:"response,omitempty"`
+	Error     string            `json:"error,omitempty"`
+	Truncated bool              `json:"truncated,omitempty"`
+}
+
+type trafficDump struct {
+	mu       sync.Mutex
+	config   TrafficDumpConfig
+	redactor *bodyRedactor
+	file     *os.File
+	gzip     *gzip.Writer
+	written  *countingWriter
+	sequence int
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func (h *ClientBuilder) TrafficDump(config TrafficDumpConfig) *ClientBuilder {
+	if config.MaxFileBytes <= 0 {
+		config.MaxFileBytes = defaultDumpFileBytes
+	}
+	if config.MaxFiles <= 0 {
+		config.MaxFiles = defaultDumpFiles
+	}
+	if config.MaxBodyBytes == 0 {
+		config.MaxBodyBytes = defaultDumpBodyBytes
+	}
+
+	if config.RedactBodyFields == nil {
+		config.RedactBodyFields = defaultRedactedBodyFields
+	}
+
+	h.trafficDump = &trafficDump{config: config, redactor: newBodyRedactor(config.RedactBodyFields)}
+	return h
+}
+
+func (c *Client) dumpTraffic(next RoundTripFunc) RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		record := TrafficRecord{
+			Time: time.Now(),
+			Request: RecordedRequest{
+				Method:  req.Method,
+				URL:     c.redactURL(req.URL.String()),
+				Headers: c.redactHeaders(req.Header),
+			},
+		}
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				record.Request.Body, record.Truncated = c.trafficDump.readBody(body)
+				record.Request.Body = c.trafficDump.redactor.redact(record.Request.Body, req.Header.Get("Content-Type"))
+				body.Close()
+			}
+		}
+
+		resp, err := next(req)
+		if err != nil {
+			record.Duration = time.Since(record.Time)
+			record.Error = c.redactError(err)
+			c.writeTrafficRecord(record)
+			return resp, err
+		}
+
+		record.Response = &RecordedResponse{
+			Status:  resp.StatusCode,
+			Headers: c.redactHeaders(resp.Header),
+		}
+		resp.Body = &dumpBody{
+			ReadCloser: resp.Body,
+			limit:      c.trafficDump.config.MaxBodyBytes,
+			done: func(body []byte, truncated bool) {
+				record.Duration = time.Since(record.Time)
+				record.Response.Body = c.trafficDump.redactor.redact(body, resp.Header.Get("Content-Type"))
+				record.Truncated = record.Truncated || truncated
+				c.writeTrafficRecord(record)
+			},
+		}
+		return resp, nil
+	}
+}
+
+func (c *Client) redactHeaders(headers http.Header) http.Header {
+	recorded := headers.Clone()
+	for name := range recorded {
+		if c.isRedactedHeader(name) || slices.ContainsFunc(dumpSensitiveHeaders, func(header string) bool {
+			return strings.EqualFold(header, name)
+		}) {
+			recorded.Set(name, redacted)
+		}
+	}
+	return recorded
+}
+
+func (c *Client) redactError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = c.redactURL(urlErr.URL)
+	}
+	return err.Error()
+}
+
+func (c *Client) writeTrafficRecord(record TrafficRecord) {
+	if err := c.trafficDump.write(record); err != nil {
+		c.logger.Error("Failed to write traffic dump",
+			"package", "reqx",
+			"error", err)
+	}
+}
+
+func (d *trafficDump) readBody(body io.Reader) ([]byte, bool) {
+	if d.config.MaxBodyBytes < 0 {
+		return nil, false
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(body, d.config.MaxBodyBytes+1))
+	if int64(len(data)) > d.config.MaxBodyBytes {
+		return data[:d.config.MaxBodyBytes], true
+	}
+	return data, false
+}
+
+func (d *trafficDump) write(record TrafficRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.gzip == nil || d.written.n >= d.config.MaxFileBytes {
+		if err := d.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := d.gzip.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return d.gzip.Flush()
+}
+
+func (d *trafficDump) rotate() error {
+	if err := d.closeFile(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(d.config.Dir, 0o755); err != nil {
+		return err
+	}
+
+	d.sequence++
+	name := fmt.Sprintf("%s%s-%04d%s", trafficDumpPrefix, time.Now().UTC().Format("20060102T150405"), d.sequence, trafficDumpSuffix)
+	file, err := os.OpenFile(filepath.Join(d.config.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	d.file = file
+	d.written = &countingWriter{w: file}
+	d.gzip = gzip.NewWriter(d.written)
+	return d.prune()
+}
+
+func (d *trafficDump) prune() error {
+	files, err := trafficDumpFiles(d.config.Dir)
+	if err != nil {
+		return err
+	}
+
+	for len(files) > d.config.MaxFiles {
+		if err := os.Remove(files[0]); err != nil {
+			return err
+		}
+		files = files[1:]
+	}
+	return nil
+}
+
+func (d *trafficDump) closeFile() error {
+	if d.gzip == nil {
+		return nil
+	}
+
+	err := errors.Join(d.gzip.Close(), d.file.Close())
+	d.gzip, d.file, d.written = nil, nil, nil
+	return err
+}
+
+func (d *trafficDump) close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_ = d.closeFile()
+}
+
+type dumpBody struct {
+	io.ReadCloser
+	buf       bytes.Buffer
+	limit     int64
+	truncated bool
+	once      sync.Once
+	done      func(body []byte, truncated bool)
+}
+
+func (b *dumpBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && b.limit >= 0 {
+		remaining := b.limit - int64(b.buf.Len())
+		if int64(n) > remaining {
+			b.truncated = true
+			b.buf.Write(p[:max(remaining, 0)])
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *dumpBody) Close() error {
+	b.finish()
+	return b.ReadCloser.Close()
+}
+
+func (b *dumpBody) finish() {
+	b.once.Do(func() {
+		b.done(b.buf.Bytes(), b.truncated)
+	})
+}
+
+func trafficDumpFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasPrefix(name, trafficDumpPrefix) && strings.HasSuffix(name, trafficDumpSuffix) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func ReadTrafficDump(path string) iter.Seq2[TrafficRecord, error] {
+	return func(yield func(TrafficRecord, error) bool) {
+		files := []string{path}
+		if info, err := os.Stat(path); err != nil {
+			yield(TrafficRecord{}, err)
+			return
+		} else if info.IsDir() {
+			if files, err = trafficDumpFiles(path); err != nil {
+				yield(TrafficRecord{}, err)
+				return
+			}
+		}
+
+		for _, file := range files {
+			if !readTrafficDumpFile(file, yield) {
+				return
+			}
+		}
+	}
+}
+
+func readTrafficDumpFile(path string, yield func(TrafficRecord, error) bool) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return yield(TrafficRecord{}, err)
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return yield(TrafficRecord{}, err)
+	}
+	defer reader.Close()
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<30)
+	for scanner.Scan() {
+		var record TrafficRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return yield(TrafficRecord{}, err)
+		}
+		if !yield(record, nil) {
+			return false
+		}
+	}
+
+	// A file that is still being written ends without a gzip trailer.
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return yield(TrafficRecord{}, err)
+	}
+	return true
+}
@@ -0,0 +1,59 @@
+package reqx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTrafficDumpRedactsCredentialBodyFields(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"at-123","token_type":"bearer","refresh_token":"rt-\"456"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClientBuilder().BaseUrl(server.URL).TrafficDump(TrafficDumpConfig{Dir: dir}).Build()
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_secret": {"cs-789"}}
+	if _, err := client.Post("/token").FormUrlencodedContentType().Body(form).DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+
+	var records []TrafficRecord
+	for record, err := range ReadTrafficDump(dir) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+	if len(records) != 1 || records[0].Response == nil {
+		t.Fatalf("got %d records, want one with a response", len(records))
+	}
+
+	request, response := string(records[0].Request.Body), string(records[0].Response.Body)
+	for _, secret := range []string{"cs-789", "at-123", "rt-"} {
+		if strings.Contains(request, secret) || strings.Contains(response, secret) {
+			t.Errorf("%q was written to the dump: %s / %s", secret, request, response)
+		}
+	}
+	if !strings.Contains(request, "grant_type=client_credentials") || !strings.Contains(response, `"token_type":"bearer"`) {
+		t.Errorf("other fields were redacted: %s / %s", request, response)
+	}
+}
+
+func TestBodyRedactionCoversTruncatedJSON(t *testing.T) {
+	redactor := newBodyRedactor(defaultRedactedBodyFields)
+	got := string(redactor.redact([]byte(`{"user":"a","Password": "hunt`), "application/json"))
+	if got != `{"user":"a","Password": "REDACTED"` {
+		t.Errorf("redacted = %s", got)
+	}
+
+	if got := newBodyRedactor([]string{}); got != nil {
+		t.Error("an empty field list still redacts")
+	}
+}
@@ -0,0 +1,179 @@
+package reqx
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Volatile headers that differ on every response are never compared.
+var replayIgnoredHeaders = []string{"Date", "Content-Length"}
+
+type ReplayConfig struct {
+	// BaseURL replaces the scheme and host of recorded URLs; its path, if
+	// any, is prefixed to the recorded path. When empty, requests go to the
+	// client's base URL.
+	BaseURL string
+	// Speed scales the recorded pacing: 1 keeps it, 10 replays ten times
+	// faster. Zero or less sends requests one after another.
+	Speed float64
+	Diff  DiffOptions
+}
+
+type ReplayResult struct {
+	Interaction Interaction
+	Response    *Response
+	Err         error
+	Diff        *ResponseDiff
+}
+
+func (r ReplayResult) Matched() bool {
+	return r.Err == nil && r.Diff != nil && r.Diff.Equal()
+}
+
+type ReplayReport struct {
+	Results    []ReplayResult
+	Matched    int
+	Mismatched int
+	Failed     int
+}
+
+func (r *ReplayReport) Mismatches() []ReplayResult {
+	var mismatches []ReplayResult
+	for _, result := range r.Results {
+		if !result.Matched() {
+			mismatches = append(mismatches, result)
+		}
+	}
+	return mismatches
+}
+
+// Paced interactions start at their scaled offset whether or not earlier
+// ones finished, so slow responses don't compress the recorded timing.
+func (c *Client) ReplayTraffic(ctx context.Context, interactions []Interaction, config ReplayConfig) (*ReplayReport, error) {
+	ctx, release := c.bind(ctx)
+	defer release()
+
+	ordered := slices.Clone(interactions)
+	slices.SortStableFunc(ordered, func(a, b Interaction) int {
+		return a.RecordedAt.Compare(b.RecordedAt)
+	})
+
+	options := config.Diff
+	options.IgnoreHeaders = append(slices.Clone(options.IgnoreHeaders), replayIgnoredHeaders...)
+
+	results := make([]ReplayResult, len(ordered))
+	sent := len(ordered)
+	var wg sync.WaitGroup
+	var err error
+
+	started := time.Now()
+	for i, interaction := range ordered {
+		var wait time.Duration
+		if config.Speed > 0 {
+			offset := interaction.RecordedAt.Sub(ordered[0].RecordedAt)
+			wait = time.Duration(float64(offset)/config.Speed) - time.Since(started)
+		}
+		if err = sleepContext(ctx, wait); err != nil {
+			err = context.Cause(ctx)
+			sent = i
+			break
+		}
+
+		if config.Speed <= 0 {
+			results[i] = c.replayInteraction(ctx, interaction, config.BaseURL, options)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.replayInteraction(ctx, interaction, config.BaseURL, options)
+		}()
+	}
+	wg.Wait()
+	results = results[:sent]
+
+	report := &ReplayReport{Results: results}
+	for _, result := range results {
+		switch {
+		case result.Err != nil:
+			report.Failed++
+		case result.Matched():
+			report.Matched++
+		default:
+			report.Mismatched++
+		}
+	}
+
+	return report, err
+}
+
+func (c *Client) replayInteraction(ctx context.Context, interaction Interaction, baseURL string, options DiffOptions) ReplayResult {
+	result := ReplayResult{Interaction: interaction}
+
+	recorded, err := url.Parse(interaction.Request.URL)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	rb := c.NewRequestBuilder().
+		Context(ctx).
+		Method(Method(interaction.Request.Method)).
+		Path(recorded.RequestURI())
+	if baseURL != "" {
+		rb.baseUrl = strings.TrimSuffix(baseURL, "/")
+	}
+	rb.queryParams = make(map[string]string)
+	rb.contentType = ContentType(interaction.Request.Headers.Get("Content-Type"))
+	for name := range interaction.Request.Headers {
+		value := interaction.Request.Headers.Get(name)
+		if value == redacted || slices.Contains(hopByHopHeaders, name) || name == "Content-Length" {
+			continue
+		}
+		rb.headers[name] = value
+	}
+	if len(interaction.Request.Body) > 0 {
+		rb.body = interaction.Request.Body
+	}
+
+	resp, err := rb.DoRaw()
+	if resp == nil {
+		result.Err = err
+		return result
+	}
+
+	expected := &Response{
+		Status:  interaction.Response.Status,
+		Headers: interaction.Response.Headers,
+		Body:    interaction.Response.Body,
+	}
+	result.Response = resp
+	result.Diff = DiffResponses(expected, resp, options)
+	return result
+}
+
+// Failed and truncated records cannot be replayed or compared faithfully.
+func TrafficDumpInteractions(path string) ([]Interaction, error) {
+	var interactions []Interaction
+	for record, err := range ReadTrafficDump(path) {
+		if err != nil {
+			return nil, err
+		}
+		if record.Response == nil || record.Truncated {
+			continue
+		}
+
+		interactions = append(interactions, Interaction{
+			Request:    record.Request,
+			Response:   *record.Response,
+			RecordedAt: record.Time,
+			Duration:   record.Duration,
+		})
+	}
+	return interactions, nil
+}
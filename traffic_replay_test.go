@@ -0,0 +1,57 @@
+package reqx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReplayTrafficStopsWhenClientCloses(t *testing.T) {
+	server, _ := captureServer(t)
+	client := NewClientBuilder().BaseUrl(server.URL).Build()
+
+	now := time.Now()
+	interactions := []Interaction{
+		{Request: RecordedRequest{Method: "GET", URL: server.URL + "/a"}, RecordedAt: now},
+		{Request: RecordedRequest{Method: "GET", URL: server.URL + "/b"}, RecordedAt: now.Add(time.Hour)},
+	}
+
+	time.AfterFunc(50*time.Millisecond, func() { client.Close() })
+
+	report, err := client.ReplayTraffic(context.Background(), interactions, ReplayConfig{Speed: 1})
+	if !errors.Is(err, ErrClientClosed) {
+		t.Fatalf("err = %v, want ErrClientClosed", err)
+	}
+	if len(report.Results) != 1 {
+		t.Errorf("replayed %d interactions, want 1", len(report.Results))
+	}
+}
+
+func TestReplayTrafficBaseURL(t *testing.T) {
+	server, captured := captureServer(t)
+	client := NewClientBuilder().Build()
+	defer client.Close()
+
+	interactions := []Interaction{{
+		Request:  RecordedRequest{Method: "GET", URL: "https://api.example.com/users/42?expand=teams"},
+		Response: RecordedResponse{Status: 200},
+	}}
+
+	for baseURL, want := range map[string]string{
+		server.URL:          "/users/42",
+		server.URL + "/v2":  "/v2/users/42",
+		server.URL + "/v2/": "/v2/users/42",
+	} {
+		report, err := client.ReplayTraffic(context.Background(), interactions, ReplayConfig{BaseURL: baseURL})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Failed != 0 {
+			t.Fatalf("%s: replay failed: %v", baseURL, report.Results[0].Err)
+		}
+		if captured.path != want || captured.query.Get("expand") != "teams" {
+			t.Errorf("%s: replayed to %s?%s, want %s?expand=teams", baseURL, captured.path, captured.query.Encode(), want)
+		}
+	}
+}
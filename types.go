@@ -2,8 +2,12 @@ package reqx
 
 import (
 	"context"
+	"crypto/rsa"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"sync"
 	"time"
 )
 
@@ -15,6 +19,7 @@ const (
 	MethodPut    Method = "PUT"
 	MethodDelete Method = "DELETE"
 	MethodPatch  Method = "PATCH"
+	MethodHead   Method = "HEAD"
 )
 
 type ContentType string
@@ -26,8 +31,11 @@ const (
 )
 
 type MultipartFormData struct {
-	Fields []FormField
-	Files  []FormFile
+	Fields     []FormField
+	Files      []FormFile
+	Checksum   ChecksumAlgorithm
+	MaxSize    int64
+	OnProgress func(progress MultipartProgress)
 }
 
 type FormField struct {
@@ -47,44 +55,157 @@ type OAuth1Config struct {
 	ConsumerSecret    string
 	AccessToken       string
 	AccessTokenSecret string
+	SignatureMethod   OAuth1SignatureMethod
+	PrivateKey        *rsa.PrivateKey
+	Realm             string
+	ExtraParams       map[string]string
 }
 
 type RetryConfig struct {
-	MaxRetries int
-	BackoffMs  int
+	MaxRetries   int
+	BackoffMs    int
+	MaxBackoffMs int
+	Strategy     BackoffStrategy
+	Jitter       JitterMode
+
+	MaxRetryAfter    time.Duration
+	IgnoreRetryAfter bool
+
+	ShouldRetry func(resp *Response, err error, attempt int) bool
 }
 
 type Client struct {
-	context     context.Context
-	client      *http.Client
-	baseUrl     string
-	timeout     time.Duration
-	queryParams map[string]string
-	headers     map[string]string
-	contentType ContentType
-	oauth1      *OAuth1Config
-	retryConfig *RetryConfig
+	context      context.Context
+	client       *http.Client
+	baseUrl      string
+	timeout      time.Duration
+	queryParams  map[string]string
+	headers      map[string]string
+	contentType  ContentType
+	oauth1       *OAuth1Config
+	retryConfig  *RetryConfig
+	successCodes []int
+	rateLimits   *rateLimitTracker
+	conditional  *conditionalCache
+	variables    map[string]string
+	envVariables map[string]bool
+	deadline     *deadlineHeader
+	phases       [phaseCount][]RequestMiddleware
+	transformers []ResponseTransformer
+
+	attemptHeader     string
+	idempotencyHeader string
+	requestIDHeader   string
+	shadow            *ShadowConfig
+	bodyTransformers  []BodyTransformer
+	middlewares       []Middleware
+	pathLimits        []*pathRateLimit
+	hooks             hooks
+	throttle          *adaptiveThrottle
+	quotas            quotas
+	auth              AuthProvider
+	firewall          *firewall
+	redactedHeaders   []string
+	redactedParams    []string
+	allowedHeaders    map[string]bool
+	urlCredentials    URLCredentialsMode
+	decodedCache      *decodedCache
+	retryRules        []RetryRule
+	srv               *srvResolver
+	trafficDump       *trafficDump
+	idGenerator       IDGenerator
+	idempotentRetries bool
+	canary            *canary
+	replayLimit       int64
+	bodyReadTimeout   time.Duration
+	errorHandler      ErrorHandler
+	fallback          Fallback
+	httpCache         *httpCache
+	tokenExpired      func(resp *Response) bool
+	hashBodies        bool
+	traceFormats      []TraceFormat
+	traceSource       TraceSource
+	capabilities      Capability
+	metrics           MetricsCollector
+	refreshes         *refreshGroup
+	debug             bool
+	debugBodyLimit    int64
+	har               *HARRecorder
+	logger            *slog.Logger
+	logLevel          slog.Leveler
+	failover          []string
+	propagatedHeaders []string
+	breaker           *circuitBreaker
+	limiter           Limiter
+	bulkhead          *bulkhead
+
+	cancel        context.CancelFunc
+	drainTimeout  time.Duration
+	mu            sync.Mutex
+	closed        bool
+	inflight      sync.WaitGroup
+	background    sync.WaitGroup
+	nextRequestID uint64
+	cancels       map[uint64]context.CancelCauseFunc
+	closing       bool
+	closers       []func()
+	releasers     []func()
 }
 
 type RequestBuilder struct {
-	client      *Client
-	method      Method
-	path        string
-	queryParams map[string]string
-	headers     map[string]string
-	contentType ContentType
-	body        any
+	client       *Client
+	context      context.Context
+	baseUrl      string
+	method       Method
+	path         string
+	queryParams  map[string]string
+	headers      map[string]string
+	contentType  ContentType
+	body         any
+	logger       *slog.Logger
+	logAttrs     []slog.Attr
+	successCodes []int
+	timeout      time.Duration
+	validator    ResponseValidator
+	reconnect    streamReconnect
+
+	bodyTransformers []BodyTransformer
+	auth             AuthProvider
+	noAuth           bool
+	templateBody     bool
+	skipDecodedCache bool
+	retryRules       []RetryRule
+	tee              io.Writer
+	allowRetry       bool
+	transport        http.RoundTripper
+	replayBuffer     []byte
+	fallback         Fallback
+	debug            bool
+	forwarded        http.Header
 }
 
 type Response struct {
-	Status     int
-	Body       []byte
-	Headers    http.Header
-	BodyReader io.ReadCloser
+	Status          int
+	Body            []byte
+	Headers         http.Header
+	BodyReader      io.ReadCloser
+	ContentEncoding string
+	Uncompressed    bool
+	FromCache       bool
+	ContentHash     string
+	Timings         Timings
+
+	AttemptRequestIDs []string
+
+	successCodes []int
 }
 
 func (r *Response) IsSuccess() bool {
-	return checkStatus(r.Status, 200, 300)
+	return checkStatus(r.Status, 200, 300) || slices.Contains(r.successCodes, r.Status)
+}
+
+func (r *Response) IsNotModified() bool {
+	return r.Status == http.StatusNotModified
 }
 
 func (r *Response) IsError() bool {
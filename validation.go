@@ -0,0 +1,390 @@
+package reqx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type ResponseValidator interface {
+	Validate(body []byte) error
+}
+
+type ValidatorFunc func(body []byte) error
+
+func (f ValidatorFunc) Validate(body []byte) error {
+	return f(body)
+}
+
+type ValidationError struct {
+	Status     int
+	Body       []byte
+	Violations []string
+	Err        error
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Violations) > 0 {
+		return fmt.Sprintf("reqx.validation_error: status %d: %s", e.Status, strings.Join(e.Violations, "; "))
+	}
+
+	return fmt.Sprintf("reqx.validation_error: status %d: %v", e.Status, e.Err)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+type SchemaViolations []string
+
+func (v SchemaViolations) Error() string {
+	return strings.Join(v, "; ")
+}
+
+type JSONSchema struct {
+	root     map[string]any
+	patterns map[string]*regexp.Regexp
+}
+
+func CompileJSONSchema(schema []byte) (*JSONSchema, error) {
+	var root map[string]any
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, err
+	}
+
+	compiled := &JSONSchema{
+		root:     root,
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	if err := compiled.compilePatterns(root); err != nil {
+		return nil, err
+	}
+
+	return compiled, nil
+}
+
+func (s *JSONSchema) compilePatterns(node any) error {
+	switch n := node.(type) {
+	case map[string]any:
+		if pattern, ok := n["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return err
+			}
+			s.patterns[pattern] = re
+		}
+		for _, child := range n {
+			if err := s.compilePatterns(child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, child := range n {
+			if err := s.compilePatterns(child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *JSONSchema) Validate(body []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+
+	var violations SchemaViolations
+	s.validate(s.root, value, "$", &violations)
+	if len(violations) > 0 {
+		return violations
+	}
+
+	return nil
+}
+
+func (s *JSONSchema) validate(schema map[string]any, value any, path string, violations *SchemaViolations) {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, found := s.resolveRef(ref)
+		if !found {
+			*violations = append(*violations, fmt.Sprintf("%s: unresolvable $ref %q", path, ref))
+			return
+		}
+		schema = resolved
+	}
+
+	if expected, ok := schema["type"]; ok && !matchesType(expected, value) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected type %v, got %s", path, expected, jsonType(value)))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, candidate := range enum {
+			if jsonEqual(candidate, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			*violations = append(*violations, fmt.Sprintf("%s: value not in enum", path))
+		}
+	}
+
+	if constant, ok := schema["const"]; ok && !jsonEqual(constant, value) {
+		*violations = append(*violations, fmt.Sprintf("%s: value does not match const", path))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		s.validateObject(schema, v, path, violations)
+	case []any:
+		s.validateArray(schema, v, path, violations)
+	case string:
+		s.validateString(schema, v, path, violations)
+	case json.Number:
+		s.validateNumber(schema, v, path, violations)
+	}
+
+	if all, ok := schema["allOf"].([]any); ok {
+		for _, sub := range all {
+			if subSchema, ok := sub.(map[string]any); ok {
+				s.validate(subSchema, value, path, violations)
+			}
+		}
+	}
+
+	if anyOf, ok := schema["anyOf"].([]any); ok && s.countMatches(anyOf, value, path) == 0 {
+		*violations = append(*violations, fmt.Sprintf("%s: value does not match any schema in anyOf", path))
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok && s.countMatches(oneOf, value, path) != 1 {
+		*violations = append(*violations, fmt.Sprintf("%s: value must match exactly one schema in oneOf", path))
+	}
+
+	if not, ok := schema["not"].(map[string]any); ok && s.countMatches([]any{not}, value, path) == 1 {
+		*violations = append(*violations, fmt.Sprintf("%s: value must not match schema in not", path))
+	}
+}
+
+func (s *JSONSchema) validateObject(schema map[string]any, value map[string]any, path string, violations *SchemaViolations) {
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if _, present := value[key]; !present {
+				*violations = append(*violations, fmt.Sprintf("%s: missing required property %q", path, key))
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(value)) {
+		child := value[key]
+		childPath := path + "." + key
+		if propSchema, ok := properties[key].(map[string]any); ok {
+			s.validate(propSchema, child, childPath, violations)
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*violations = append(*violations, fmt.Sprintf("%s: additional property not allowed", childPath))
+			}
+		case map[string]any:
+			s.validate(additional, child, childPath, violations)
+		}
+	}
+}
+
+func (s *JSONSchema) validateArray(schema map[string]any, value []any, path string, violations *SchemaViolations) {
+	if minItems, ok := schemaNumber(schema, "minItems"); ok && float64(len(value)) < minItems {
+		*violations = append(*violations, fmt.Sprintf("%s: expected at least %v items", path, minItems))
+	}
+	if maxItems, ok := schemaNumber(schema, "maxItems"); ok && float64(len(value)) > maxItems {
+		*violations = append(*violations, fmt.Sprintf("%s: expected at most %v items", path, maxItems))
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range value {
+			s.validate(items, item, fmt.Sprintf("%s[%d]", path, i), violations)
+		}
+	}
+}
+
+func (s *JSONSchema) validateString(schema map[string]any, value string, path string, violations *SchemaViolations) {
+	length := float64(len([]rune(value)))
+	if minLength, ok := schemaNumber(schema, "minLength"); ok && length < minLength {
+		*violations = append(*violations, fmt.Sprintf("%s: expected length >= %v", path, minLength))
+	}
+	if maxLength, ok := schemaNumber(schema, "maxLength"); ok && length > maxLength {
+		*violations = append(*violations, fmt.Sprintf("%s: expected length <= %v", path, maxLength))
+	}
+
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re := s.patterns[pattern]; re != nil && !re.MatchString(value) {
+			*violations = append(*violations, fmt.Sprintf("%s: does not match pattern %q", path, pattern))
+		}
+	}
+}
+
+func (s *JSONSchema) validateNumber(schema map[string]any, value json.Number, path string, violations *SchemaViolations) {
+	number, err := value.Float64()
+	if err != nil {
+		return
+	}
+
+	if minimum, ok := schemaNumber(schema, "minimum"); ok && number < minimum {
+		*violations = append(*violations, fmt.Sprintf("%s: expected >= %v", path, minimum))
+	}
+	if maximum, ok := schemaNumber(schema, "maximum"); ok && number > maximum {
+		*violations = append(*violations, fmt.Sprintf("%s: expected <= %v", path, maximum))
+	}
+	if minimum, ok := schemaNumber(schema, "exclusiveMinimum"); ok && number <= minimum {
+		*violations = append(*violations, fmt.Sprintf("%s: expected > %v", path, minimum))
+	}
+	if maximum, ok := schemaNumber(schema, "exclusiveMaximum"); ok && number >= maximum {
+		*violations = append(*violations, fmt.Sprintf("%s: expected < %v", path, maximum))
+	}
+}
+
+func (s *JSONSchema) countMatches(schemas []any, value any, path string) int {
+	matches := 0
+	for _, sub := range schemas {
+		subSchema, ok := sub.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var subViolations SchemaViolations
+		s.validate(subSchema, value, path, &subViolations)
+		if len(subViolations) == 0 {
+			matches++
+		}
+	}
+
+	return matches
+}
+
+func (s *JSONSchema) resolveRef(ref string) (map[string]any, bool) {
+	if ref == "#" {
+		return s.root, true
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, false
+	}
+
+	var node any = s.root
+	for _, segment := range strings.Split(ref[2:], "/") {
+		segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		node = object[segment]
+	}
+
+	resolved, ok := node.(map[string]any)
+	return resolved, ok
+}
+
+func schemaNumber(schema map[string]any, key string) (float64, bool) {
+	number, ok := schema[key].(float64)
+	return number, ok
+}
+
+func matchesType(expected any, value any) bool {
+	switch t := expected.(type) {
+	case string:
+		return matchesTypeName(t, value)
+	case []any:
+		for _, candidate := range t {
+			if name, ok := candidate.(string); ok && matchesTypeName(name, value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return true
+}
+
+func matchesTypeName(name string, value any) bool {
+	actual := jsonType(value)
+	if name == "number" && actual == "integer" {
+		return true
+	}
+
+	return name == actual
+}
+
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	}
+
+	return "unknown"
+}
+
+// Schemas are decoded with float64 numbers and responses with json.Number,
+// so numbers are compared by value at every level.
+func jsonEqual(schemaValue any, value any) bool {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return false
+		}
+		expected, ok := schemaValue.(float64)
+		return ok && expected == f
+	case []any:
+		expected, ok := schemaValue.([]any)
+		if !ok || len(expected) != len(v) {
+			return false
+		}
+		for i := range v {
+			if !jsonEqual(expected[i], v[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		expected, ok := schemaValue.(map[string]any)
+		if !ok || len(expected) != len(v) {
+			return false
+		}
+		for key, item := range v {
+			want, ok := expected[key]
+			if !ok || !jsonEqual(want, item) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(schemaValue, value)
+}
@@ -0,0 +1,31 @@
+package reqx
+
+import "testing"
+
+func TestJSONSchemaConstAndEnumCompareNestedValues(t *testing.T) {
+	tests := []struct {
+		schema  string
+		body    string
+		invalid bool
+	}{
+		{schema: `{"const":{"a":1}}`, body: `{"a":1}`},
+		{schema: `{"const":{"a":1}}`, body: `{"a":2}`, invalid: true},
+		{schema: `{"const":{"a":1}}`, body: `{"a":1,"b":2}`, invalid: true},
+		{schema: `{"const":{"a":[1,{"b":2.5}]}}`, body: `{"a":[1,{"b":2.5}]}`},
+		{schema: `{"enum":[[1,2],"x"]}`, body: `[1,2]`},
+		{schema: `{"enum":[[1,2],"x"]}`, body: `[2,1]`, invalid: true},
+		{schema: `{"enum":[{"id":3}]}`, body: `{"id":3}`},
+		{schema: `{"enum":[1,2]}`, body: `2`},
+	}
+
+	for _, test := range tests {
+		schema, err := CompileJSONSchema([]byte(test.schema))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = schema.Validate([]byte(test.body))
+		if invalid := err != nil; invalid != test.invalid {
+			t.Errorf("%s against %s: err = %v, want invalid = %t", test.body, test.schema, err, test.invalid)
+		}
+	}
+}
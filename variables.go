@@ -0,0 +1,49 @@
+package reqx
+
+import (
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// TemplateBody expands placeholders in a string, byte or form body. Bodies
+// often carry caller data, which could otherwise pull variables, including
+// environment secrets, into the request, so expansion is opt-in.
+func (c *RequestBuilder) TemplateBody() *RequestBuilder {
+	c.templateBody = true
+	return c
+}
+
+// The base URL, paths and headers set on the client builder are templates.
+// Query values and request headers are sent as given.
+func (c *Client) expandVariables(s string) string {
+	if (len(c.variables) == 0 && len(c.envVariables) == 0) || !strings.Contains(s, "{{") {
+		return s
+	}
+
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if value, ok := c.variables[name]; ok {
+			return value
+		}
+		if c.envVariables[name] {
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+		}
+		return match
+	})
+}
+
+func (c *Client) expandForm(form url.Values) url.Values {
+	expanded := make(url.Values, len(form))
+	for k, values := range form {
+		for _, v := range values {
+			expanded.Add(k, c.expandVariables(v))
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,112 @@
+package reqx
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type capturedRequest struct {
+	path   string
+	query  url.Values
+	header http.Header
+	body   string
+}
+
+func captureServer(t *testing.T) (*httptest.Server, *capturedRequest) {
+	t.Helper()
+	captured := &capturedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*captured = capturedRequest{path: r.URL.Path, query: r.URL.Query(), header: r.Header.Clone(), body: string(body)}
+	}))
+	t.Cleanup(server.Close)
+	return server, captured
+}
+
+func TestVariablesOnlyExpandInPathAndBaseURL(t *testing.T) {
+	t.Setenv("REQX_TEST_SECRET", "s3cret")
+	t.Setenv("REQX_TEST_TENANT", "acme")
+
+	server, captured := captureServer(t)
+	client := NewClientBuilder().
+		BaseUrl("{{host}}").
+		Variable("host", server.URL).
+		Variable("id", "42").
+		EnvVariables("REQX_TEST_SECRET", "REQX_TEST_TENANT").
+		Build()
+	defer client.Close()
+
+	_, err := client.Post("/tenants/{{REQX_TEST_TENANT}}/users/{{id}}").
+		QueryParam("q", "{{REQX_TEST_SECRET}}").
+		Header("X-Note", "{{REQX_TEST_SECRET}}").
+		Body("{{REQX_TEST_SECRET}} {{id}}").
+		DoRaw()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if captured.path != "/tenants/acme/users/42" {
+		t.Errorf("path = %q", captured.path)
+	}
+	if got := captured.query.Get("q"); got != "{{REQX_TEST_SECRET}}" {
+		t.Errorf("query = %q", got)
+	}
+	if got := captured.header.Get("X-Note"); got != "{{REQX_TEST_SECRET}}" {
+		t.Errorf("header = %q", got)
+	}
+	if captured.body != "{{REQX_TEST_SECRET}} {{id}}" {
+		t.Errorf("body = %q", captured.body)
+	}
+}
+
+func TestEnvVariablesRequireAllowList(t *testing.T) {
+	t.Setenv("REQX_TEST_SECRET", "s3cret")
+	t.Setenv("REQX_TEST_TENANT", "acme")
+
+	server, captured := captureServer(t)
+	client := NewClientBuilder().BaseUrl(server.URL).EnvVariables("REQX_TEST_TENANT").Build()
+	defer client.Close()
+
+	if _, err := client.Get("/{{REQX_TEST_TENANT}}/{{REQX_TEST_SECRET}}").DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(captured.path, "s3cret") || !strings.HasPrefix(captured.path, "/acme/") {
+		t.Errorf("path = %q", captured.path)
+	}
+}
+
+func TestVariablesExpandInClientHeadersAndOptedInBodies(t *testing.T) {
+	t.Setenv("REQX_TEST_TENANT", "acme")
+	t.Setenv("REQX_TEST_SECRET", "s3cret")
+
+	server, captured := captureServer(t)
+	client := NewClientBuilder().
+		BaseUrl(server.URL).
+		Variable("id", "42").
+		EnvVariables("REQX_TEST_TENANT").
+		Header("X-Tenant", "{{REQX_TEST_TENANT}}").
+		Build()
+	defer client.Close()
+
+	if _, err := client.Post("/").Body("{{id}} {{REQX_TEST_SECRET}}").TemplateBody().DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if got := captured.header.Get("X-Tenant"); got != "acme" {
+		t.Errorf("client header = %q, want it expanded", got)
+	}
+	if captured.body != "42 {{REQX_TEST_SECRET}}" {
+		t.Errorf("body = %q, want variables expanded and unlisted env names kept", captured.body)
+	}
+
+	form := url.Values{"id": {"{{id}}"}}
+	if _, err := client.Post("/").FormUrlencodedContentType().Body(form).TemplateBody().DoRaw(); err != nil {
+		t.Fatal(err)
+	}
+	if captured.body != "id=42" {
+		t.Errorf("form body = %q", captured.body)
+	}
+}
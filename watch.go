@@ -0,0 +1,67 @@
+package reqx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+type WatchResource[T any] struct {
+	client *Client
+	path   string
+
+	mu           sync.Mutex
+	etag         string
+	lastModified string
+	body         []byte
+	value        T
+}
+
+func NewWatchResource[T any](client *Client, path string) *WatchResource[T] {
+	return &WatchResource[T]{client: client, path: path}
+}
+
+func (w *WatchResource[T]) Fetch(ctx context.Context) (T, bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	rb := w.client.Get(w.path).Context(ctx)
+	if w.etag != "" {
+		rb.Header("If-None-Match", w.etag)
+	}
+	if w.lastModified != "" {
+		rb.Header("If-Modified-Since", w.lastModified)
+	}
+
+	resp, err := rb.DoRaw()
+	if err != nil {
+		return w.value, false, err
+	}
+	if resp.IsNotModified() {
+		return w.value, false, nil
+	}
+
+	w.etag = resp.Headers.Get("ETag")
+	w.lastModified = resp.Headers.Get("Last-Modified")
+
+	if w.body != nil && bytes.Equal(w.body, resp.Body) {
+		return w.value, false, nil
+	}
+
+	var value T
+	if err := json.Unmarshal(resp.Body, &value); err != nil {
+		return w.value, false, &DecodeError{Status: resp.Status, Body: resp.Body, Err: err}
+	}
+
+	w.body = resp.Body
+	w.value = value
+	return value, true, nil
+}
+
+func (w *WatchResource[T]) Value() T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.value
+}